@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// AchievementComment adalah satu komentar di thread prestasi. Balasan selalu
+// menempel ke komentar teratas (thread satu tingkat).
+type AchievementComment struct {
+	ID            string  `json:"id"`
+	AchievementID string  `json:"achievement_id"`
+	ParentID      *string `json:"parent_id,omitempty"`
+	AuthorID      string  `json:"author_id"`
+	AuthorName    string  `json:"author_name"`
+	AuthorRole    string  `json:"author_role"`
+	Body          string  `json:"body"`
+	// file_url salah satu attachment prestasi yang dirujuk komentar
+	AttachmentRef *string `json:"attachment_ref,omitempty"`
+	// hanya terlihat oleh role dengan izin achievement:comment:internal
+	Internal  bool                 `json:"internal"`
+	CreatedAt time.Time            `json:"created_at"`
+	Replies   []AchievementComment `json:"replies,omitempty"`
+}
+
+type CreateCommentRequest struct {
+	Body          string  `json:"body"`
+	ParentID      *string `json:"parent_id"`
+	AttachmentRef *string `json:"attachment_ref"`
+	Internal      bool    `json:"internal"`
+}
+
+type AchievementCommentsResponse struct {
+	Status string               `json:"status"`
+	Data   []AchievementComment `json:"data"`
+	Meta   struct {
+		Total int `json:"total"`
+		// komentar yang belum dibaca sebelum request ini (thread otomatis ditandai terbaca)
+		Unread int `json:"unread"`
+	} `json:"meta"`
+}
+
+type AchievementCommentResponse struct {
+	Status string             `json:"status"`
+	Data   AchievementComment `json:"data"`
+}
@@ -14,6 +14,8 @@ type Achievement struct {
 	Points          int                    `json:"points" bson:"points"`
 	CreatedAt       time.Time              `json:"created_at" bson:"createdAt"`
 	UpdatedAt       time.Time              `json:"updated_at" bson:"updatedAt"`
+	// naik setiap kali dokumen berubah, dikirim sebagai ETag
+	Version int `json:"version" bson:"version"`
 }
 
 type Attachment struct {
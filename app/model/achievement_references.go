@@ -8,14 +8,59 @@ type AchievementReference struct {
 	MongoAchievementID string     `json:"mongo_achievement_id"`
 	Status             string     `json:"status"`
 	SubmittedAt        *time.Time `json:"submitted_at"`
+	ReviewStartedAt    *time.Time `json:"review_started_at"`
 	VerifiedAt         *time.Time `json:"verified_at"`
 	VerifiedBy         *string    `json:"verified_by"`
 	RejectionNote      *string    `json:"rejection_note"`
+	RevisionNote       *string    `json:"revision_note"`
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
+	SuggestedPoints    *int       `json:"suggested_points"`
+	AwardedPoints      *int       `json:"awarded_points"`
+	PointsOverride     *string    `json:"points_override_reason"`
+	StudentName        string     `json:"student_name,omitempty"`
+	RejectionCount     int        `json:"rejection_count"`
+	// nilai kolom sort list dalam bentuk teks, bahan cursor halaman berikutnya
+	SortKey *string `json:"-"`
 }
 
+// filter tambahan untuk list prestasi (kolom achievement_references).
+// Semua rentang tanggal: From inklusif, To eksklusif.
+type AchievementListFilter struct {
+	Status    string
+	StudentID string
+	// lecturers.id, hanya dipakai untuk admin
+	AdvisorID     string
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	SubmittedFrom *time.Time
+	SubmittedTo   *time.Time
+	VerifiedFrom  *time.Time
+	VerifiedTo    *time.Time
+	// rentang awarded_points, inklusif
+	PointsMin *int
+	PointsMax *int
+	// hasil filter dokumen Mongo (AchievementSearchFilter); nil = tidak difilter
+	MongoIDs []string
+}
+
+// filter yang hanya bisa dijawab dokumen Mongo
+type AchievementSearchFilter struct {
+	AchievementType  string
+	Tags             []string
+	CompetitionLevel string
+	// dicari lewat text index (title, description, tags, details), per kata
+	Query string
+}
+
+func (f AchievementSearchFilter) IsEmpty() bool {
+	return f.AchievementType == "" && len(f.Tags) == 0 && f.CompetitionLevel == "" && f.Query == ""
+}
+
+// Points kosong = pakai poin usulan sistem. Kalau berbeda dari usulan,
+// OverrideReason wajib diisi.
 type VerifyAchievementRequest struct {
-    Points int `json:"points"`
+	Points         *int   `json:"points" example:"75"`
+	OverrideReason string `json:"override_reason"`
 }
 
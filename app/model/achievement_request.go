@@ -7,7 +7,6 @@ type CreateAchievementRequest struct {
 	Title           string                 `json:"title"`
 	Description     string                 `json:"description"`
 	Details         map[string]interface{} `json:"details"`
-	Points          int                    `json:"points" example:"50"`
 	Tags            []string               `json:"tags"`
 }
 
@@ -28,8 +27,10 @@ type SubmitAchievementResponse struct {
 }
 
 type SubmitAchievementData struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
+	ID              string `json:"id"`
+	Status          string `json:"status"`
+	SuggestedPoints int    `json:"suggested_points"`
+	SubmissionNo    int    `json:"submission_no"`
 }
 
 type AchievementStatusResponse struct {
@@ -75,22 +76,25 @@ type UploadAttachmentResponse struct {
 }
 
 type UpdateAchievementRequest struct {
-	Title       *string                `json:"title"`
-	Description *string                `json:"description"`
-	Details     map[string]interface{} `json:"details"`
-	Points      *int                   `json:"points" example:"100"`
-	Tags        *[]string              `json:"tags"`
+	AchievementType *string                `json:"achievement_type"`
+	Title           *string                `json:"title"`
+	Description     *string                `json:"description"`
+	Details         map[string]interface{} `json:"details"`
+	Tags            *[]string              `json:"tags"`
 }
 
 type RejectionNote struct {
 	Note string `json:"note"`
 }
 
+// HistoryItem adalah satu baris achievement_status_history.
+// FromStatus kosong untuk baris pertama (draft dibuat).
 type HistoryItem struct {
-    Status    string    `json:"status"`
-    UpdatedAt time.Time `json:"updated_at"`
-    UpdatedBy string    `json:"updated_by"`
-    Note      string    `json:"note,omitempty"`
+    FromStatus string    `json:"from_status,omitempty"`
+    Status     string    `json:"status"`
+    UpdatedAt  time.Time `json:"updated_at"`
+    UpdatedBy  string    `json:"updated_by"`
+    Note       string    `json:"note,omitempty"`
 }
 
 type AchievementHistoryResponse struct {
@@ -0,0 +1,40 @@
+package model
+
+// field details bertipe teks yang ikut diindeks pencarian
+var SearchableDetailFields = []string{
+	"competitionName",
+	"organizer",
+	"publicationTitle",
+	"publisher",
+	"authors",
+	"organizationName",
+	"position",
+	"certificationName",
+	"issuedBy",
+	"awardName",
+}
+
+type AchievementSearchHit struct {
+	ID              string  `json:"id"`
+	MongoID         string  `json:"mongo_id"`
+	StudentID       string  `json:"student_id"`
+	Status          string  `json:"status"`
+	AchievementType string  `json:"achievement_type"`
+	Title           string  `json:"title"`
+	Score           float64 `json:"score"`
+	// potongan teks per field dengan kata yang cocok dibungkus <mark></mark>
+	Highlights map[string]string `json:"highlights"`
+}
+
+type AchievementSearchResponse struct {
+	Status string `json:"status"`
+	Meta   struct {
+		Query string `json:"query"`
+		Total int    `json:"total"`
+		// true kalau kandidat dari MongoDB mencapai batas; total hanya batas bawah
+		TotalCapped bool `json:"total_capped"`
+		// true kalau hasil berasal dari pencocokan toleran salah ketik
+		Fuzzy bool `json:"fuzzy"`
+	} `json:"meta"`
+	Data []AchievementSearchHit `json:"data"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import "slices"
+
+// status prestasi di achievement_references
+const (
+	StatusDraft             = "draft"
+	StatusSubmitted         = "submitted"
+	StatusVerified          = "verified"
+	StatusRejected          = "rejected"
+	StatusRevisionRequested = "revision_requested"
+	StatusDeleted           = "deleted"
+)
+
+var AchievementStatusMap = map[string]string{
+	StatusDraft:             "Draft",
+	StatusSubmitted:         "Diajukan",
+	StatusVerified:          "Terverifikasi",
+	StatusRejected:          "Ditolak",
+	StatusRevisionRequested: "Perlu Revisi",
+	StatusDeleted:           "Dihapus",
+}
+
+// aksi yang mengubah status prestasi
+const (
+	ActionSubmit          = "submit"
+	ActionVerify          = "verify"
+	ActionReject          = "reject"
+	ActionRequestRevision = "request_revision"
+	ActionWithdraw        = "withdraw"
+	ActionDelete          = "delete"
+	// rekonsiliasi: reference yang dokumen Mongo-nya hilang
+	ActionQuarantine = "quarantine"
+)
+
+type StatusTransition struct {
+	From []string
+	To   string
+}
+
+// AchievementTransitions adalah satu-satunya sumber aturan perpindahan status.
+// Guard SQL di repository memakai From dari tabel ini.
+var AchievementTransitions = map[string]StatusTransition{
+	ActionSubmit:          {From: []string{StatusDraft, StatusRejected, StatusRevisionRequested}, To: StatusSubmitted},
+	ActionVerify:          {From: []string{StatusSubmitted}, To: StatusVerified},
+	ActionReject:          {From: []string{StatusSubmitted}, To: StatusRejected},
+	ActionRequestRevision: {From: []string{StatusSubmitted}, To: StatusRevisionRequested},
+	ActionWithdraw:        {From: []string{StatusSubmitted}, To: StatusDraft},
+	ActionDelete:          {From: []string{StatusDraft}, To: StatusDeleted},
+	ActionQuarantine:      {From: []string{StatusDraft, StatusSubmitted}, To: StatusDeleted},
+}
+
+// CanTransition mengecek apakah aksi boleh dilakukan dari status saat ini
+func CanTransition(action, from string) bool {
+	t, ok := AchievementTransitions[action]
+	return ok && slices.Contains(t.From, from)
+}
+
+// ReviewDecisions adalah status hasil keputusan dosen wali atas pengajuan
+func ReviewDecisions() []string {
+	return []string{
+		AchievementTransitions[ActionVerify].To,
+		AchievementTransitions[ActionReject].To,
+		AchievementTransitions[ActionRequestRevision].To,
+	}
+}
+
+// EditableStatuses adalah status yang masih boleh diubah mahasiswa,
+// yaitu status yang bisa diajukan (ulang)
+func EditableStatuses() []string {
+	return AchievementTransitions[ActionSubmit].From
+}
@@ -0,0 +1,104 @@
+package model
+
+import "time"
+
+// tipe field details yang didukung validator
+const (
+	FieldString      = "string"
+	FieldNumber      = "number"
+	FieldDate        = "date" // YYYY-MM-DD
+	FieldEnum        = "enum"
+	FieldStringArray = "string_array"
+)
+
+var DetailFieldTypes = map[string]bool{
+	FieldString:      true,
+	FieldNumber:      true,
+	FieldDate:        true,
+	FieldEnum:        true,
+	FieldStringArray: true,
+}
+
+type AchievementField struct {
+	Name     string   `json:"name"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Enum     []string `json:"enum,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+}
+
+// DetailSchema disimpan sebagai JSONB di kolom achievement_types.detail_schema
+type DetailSchema struct {
+	Fields []AchievementField `json:"fields"`
+	// field di luar schema boleh disimpan (mis. tipe "other")
+	AllowExtra bool `json:"allow_extra"`
+}
+
+// operasi rule poin
+const (
+	PointOpAdd      = "add"
+	PointOpMultiply = "multiply"
+)
+
+// PointRule menghitung poin dari nilai satu field di details.
+// add: poin ditambah Values[nilai], mis. competitionLevel international=100.
+// multiply: poin dikali Values[nilai], mis. rank "1"=1, "2"=0.8.
+// Default dipakai kalau nilai tidak ada di Values (kosong = 0 untuk add, 1 untuk multiply).
+type PointRule struct {
+	Field   string             `json:"field"`
+	Op      string             `json:"op"`
+	Values  map[string]float64 `json:"values"`
+	Default *float64           `json:"default,omitempty"`
+}
+
+// AchievementType adalah katalog jenis prestasi yang dikelola admin
+type AchievementType struct {
+	Code        string       `json:"code"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Schema      DetailSchema `json:"detail_schema"`
+	BasePoints  int          `json:"base_points"`
+	PointRules  []PointRule  `json:"point_rules"`
+	IsActive    bool         `json:"is_active"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type AchievementTypeRequest struct {
+	Code        string       `json:"code" example:"competition"`
+	Name        string       `json:"name" example:"Kompetisi"`
+	Description string       `json:"description"`
+	Schema      DetailSchema `json:"detail_schema"`
+	BasePoints  int          `json:"base_points" example:"10"`
+	PointRules  []PointRule  `json:"point_rules"`
+	IsActive    *bool        `json:"is_active"`
+}
+
+type AchievementTypeResponse struct {
+	Status string          `json:"status"`
+	Data   AchievementType `json:"data"`
+}
+
+type AchievementTypeListResponse struct {
+	Status string            `json:"status"`
+	Data   []AchievementType `json:"data"`
+}
+
+type PointsPreviewResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		AchievementType string `json:"achievement_type"`
+		SuggestedPoints int    `json:"suggested_points"`
+	} `json:"data"`
+}
+
+type DetailFieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type DetailsValidationResponse struct {
+	Error  string             `json:"error"`
+	Fields []DetailFieldError `json:"fields"`
+}
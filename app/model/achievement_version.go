@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+const (
+	SnapshotReasonSubmit = "submit"
+	SnapshotReasonEdit   = "edit"
+)
+
+// AchievementSnapshot adalah salinan dokumen achievements yang tidak pernah diubah
+// setelah ditulis. Version sama dengan field version dokumen saat disalin.
+type AchievementSnapshot struct {
+	AchievementID string                 `json:"achievement_id" bson:"achievementId"` // _id dokumen achievements
+	Version       int                    `json:"version" bson:"version"`
+	Reason        string                 `json:"reason" bson:"reason"`
+	CreatedAt     time.Time              `json:"created_at" bson:"createdAt"`
+	CreatedBy     string                 `json:"created_by" bson:"createdBy"`
+	Document      map[string]interface{} `json:"document,omitempty" bson:"document,omitempty"`
+}
+
+// AchievementSubmission adalah satu baris achievement_submissions
+type AchievementSubmission struct {
+	SubmissionNo    int
+	SubmittedAt     time.Time
+	DocumentVersion *int
+	Decision        *string
+	DecidedAt       *time.Time
+	Note            *string
+}
+
+type AchievementVersionItem struct {
+	Version      int       `json:"version"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+	CreatedBy    string    `json:"created_by"`
+	SubmissionNo *int      `json:"submission_no,omitempty"`
+	// keputusan dosen untuk pengajuan ini (verified, rejected, ...), kosong kalau belum diputus
+	Decision *string `json:"decision,omitempty"`
+	Note     *string `json:"note,omitempty"`
+	Verified bool    `json:"verified"`
+}
+
+type AchievementVersionsResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		CurrentVersion  int                      `json:"current_version"`
+		VerifiedVersion *int                     `json:"verified_version"`
+		Versions        []AchievementVersionItem `json:"versions"`
+	} `json:"data"`
+}
+
+type AchievementVersionResponse struct {
+	Status string              `json:"status"`
+	Data   AchievementSnapshot `json:"data"`
+}
+
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// DiffChange: Path memakai notasi titik, index array ditulis sebagai angka (tags.0)
+type DiffChange struct {
+	Path string      `json:"path"`
+	Op   string      `json:"op"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+type AchievementDiffResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		From    int          `json:"from"`
+		To      int          `json:"to"`
+		Changes []DiffChange `json:"changes"`
+	} `json:"data"`
+}
@@ -1,7 +1,61 @@
 package model
 
+import "time"
+
 type Lecturer struct {
-	ID     string `json:"id"`
-	UserID string `json:"user_id"`
-	Name   string `json:"name"`
+	ID           string  `json:"id"`
+	UserID       string  `json:"user_id"`
+	Name         string  `json:"name"`
+	Email        string  `json:"email"`
+	NIDN         string  `json:"nidn"`
+	OrgUnitID    *string `json:"org_unit_id"`
+	Department   string  `json:"department"`
+	IsActive     bool    `json:"is_active"`
+	AdviseeCount int     `json:"advisee_count"`
+}
+
+type LecturerListResponse struct {
+	Status string     `json:"status"`
+	Data   []Lecturer `json:"data"`
+}
+
+type LecturerDetailResponse struct {
+	Status string   `json:"status"`
+	Data   Lecturer `json:"data"`
+}
+
+type CreateLecturerRequest struct {
+	UserID    string  `json:"user_id"`
+	NIDN      string  `json:"nidn" example:"0012345678"`
+	OrgUnitID *string `json:"org_unit_id"`
+}
+
+type UpdateLecturerRequest struct {
+	NIDN      string  `json:"nidn" example:"0012345678"`
+	OrgUnitID *string `json:"org_unit_id"`
+}
+
+type DashboardDecision struct {
+	AchievementID string    `json:"achievement_id"`
+	StudentID     string    `json:"student_id"`
+	StudentName   string    `json:"student_name"`
+	Status        string    `json:"status"`
+	DecidedAt     time.Time `json:"decided_at"`
+	Note          *string   `json:"note,omitempty"`
+}
+
+type LecturerDashboard struct {
+	Lecturer                 Lecturer            `json:"lecturer"`
+	Advisees                 []AdviseeResponse   `json:"advisees"`
+	TotalPending             int                 `json:"total_pending"`
+	OldestPendingSubmittedAt *time.Time          `json:"oldest_pending_submitted_at"`
+	OldestPendingAgeHours    *int                `json:"oldest_pending_age_hours"`
+	RecentDecisions          []DashboardDecision `json:"recent_decisions"`
+	SemesterStart            time.Time           `json:"semester_start"`
+	InactiveAdvisees         []AdviseeResponse   `json:"inactive_advisees"`
+}
+
+type LecturerDashboardResponse struct {
+	Status string            `json:"status"`
+	Data   LecturerDashboard `json:"data"`
 }
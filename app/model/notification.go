@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// jenis notifikasi
+const (
+	NotificationAchievementWithdrawn = "achievement_withdrawn"
+	NotificationAchievementComment   = "achievement_comment"
+)
+
+type Notification struct {
+	ID            string     `json:"id"`
+	UserID        string     `json:"user_id"`
+	Type          string     `json:"type"`
+	Title         string     `json:"title"`
+	Message       string     `json:"message"`
+	AchievementID *string    `json:"achievement_id"`
+	ReadAt        *time.Time `json:"read_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type NotificationListResponse struct {
+	Status string         `json:"status"`
+	Data   []Notification `json:"data"`
+	Meta   struct {
+		Unread int `json:"unread"`
+	} `json:"meta"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// jenis unit organisasi, berjenjang fakultas → jurusan → program studi
+const (
+	OrgUnitFaculty      = "faculty"
+	OrgUnitDepartment   = "department"
+	OrgUnitStudyProgram = "study_program"
+)
+
+// parent yang wajib untuk tiap jenis unit ("" = tidak punya parent)
+var OrgUnitParentType = map[string]string{
+	OrgUnitFaculty:      "",
+	OrgUnitDepartment:   OrgUnitFaculty,
+	OrgUnitStudyProgram: OrgUnitDepartment,
+}
+
+type OrgUnit struct {
+	ID        string    `json:"id"`
+	ParentID  *string   `json:"parent_id"`
+	Type      string    `json:"type"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OrgUnitRequest struct {
+	ParentID *string `json:"parent_id"`
+	Type     string  `json:"type" example:"study_program"`
+	Code     string  `json:"code" example:"TI"`
+	Name     string  `json:"name" example:"Teknik Informatika"`
+	IsActive *bool   `json:"is_active"`
+}
+
+type OrgUnitResponse struct {
+	Status string  `json:"status"`
+	Data   OrgUnit `json:"data"`
+}
+
+type OrgUnitListResponse struct {
+	Status string    `json:"status"`
+	Data   []OrgUnit `json:"data"`
+}
+
+type AssignOrgUnitRequest struct {
+	OrgUnitID *string `json:"org_unit_id"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jenis event outbox, masing-masing punya payload sendiri
+const (
+	OutboxAchievementDraftCreated  = "achievement.draft_created"
+	OutboxAchievementPointsAwarded = "achievement.points_awarded"
+)
+
+// OutboxEvent ditulis di transaksi Postgres yang sama dengan perubahan
+// achievement_references, lalu diterapkan ke MongoDB oleh dispatcher
+type OutboxEvent struct {
+	ID          string          `json:"id"`
+	AggregateID string          `json:"aggregate_id"`
+	EventType   string          `json:"event_type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	LastError   *string         `json:"last_error,omitempty"`
+	AvailableAt time.Time       `json:"available_at"`
+	ProcessedAt *time.Time      `json:"processed_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type DraftCreatedPayload struct {
+	MongoID     string      `json:"mongo_id"`
+	Achievement Achievement `json:"achievement"`
+}
+
+type PointsAwardedPayload struct {
+	MongoID string `json:"mongo_id"`
+	Points  int    `json:"points"`
+}
@@ -0,0 +1,22 @@
+package model
+
+// ListCursor adalah posisi terakhir di mode keyset: nilai kolom sort
+// (teks dari Postgres, nil kalau NULL) dan id sebagai pemecah seri
+type ListCursor struct {
+	Sort  string  `json:"s"`
+	Order string  `json:"o"`
+	Value *string `json:"v"`
+	ID    string  `json:"id"`
+}
+
+// PageRequest dipakai semua list prestasi. After terisi = mode cursor
+// (Offset diabaikan); mode cursor hanya menghitung total kalau CountTotal.
+type PageRequest struct {
+	Limit      int
+	Offset     int
+	SortBy     string
+	Order      string
+	Cursor     bool
+	After      *ListCursor
+	CountTotal bool
+}
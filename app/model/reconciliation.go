@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// jenis temuan rekonsiliasi Postgres ↔ MongoDB
+const (
+	ReconcileMissingDocument = "missing_document" // reference tanpa dokumen Mongo
+	ReconcileOrphanDocument  = "orphan_document"  // dokumen Mongo tanpa reference
+	ReconcileStudentMismatch = "student_mismatch"
+	ReconcilePointsMismatch  = "points_mismatch"
+	ReconcileStatusMismatch  = "status_mismatch"
+)
+
+// tindakan yang (akan) diambil untuk satu temuan
+const (
+	ReconcileActionNone            = "none"
+	ReconcileActionWouldRepair     = "would_repair"
+	ReconcileActionRepaired        = "repaired"
+	ReconcileActionWouldQuarantine = "would_quarantine"
+	ReconcileActionQuarantined     = "quarantined"
+	ReconcileActionFailed          = "failed"
+)
+
+// ReconcileReference adalah data achievement_references yang dibandingkan
+type ReconcileReference struct {
+	ID            string
+	StudentID     string
+	MongoID       string
+	Status        string
+	AwardedPoints *int
+	// masih ada event outbox yang belum diproses atau reference baru dibuat;
+	// Mongo memang belum tentu sinkron
+	PendingOutbox bool
+}
+
+// ReconcileDocument adalah proyeksi dokumen achievements yang dibandingkan
+type ReconcileDocument struct {
+	MongoID   string
+	StudentID string
+	Points    int
+	// hanya ada di dokumen lama yang masih menyimpan status sendiri
+	Status *string
+}
+
+type ReconcileRequest struct {
+	// default true; harus dikirim false secara eksplisit untuk mengubah data
+	DryRun     *bool `json:"dry_run" example:"true"`
+	Repair     bool  `json:"repair"`
+	Quarantine bool  `json:"quarantine"`
+}
+
+type ReconcileIssue struct {
+	Kind        string      `json:"kind"`
+	ReferenceID string      `json:"reference_id,omitempty"`
+	MongoID     string      `json:"mongo_id,omitempty"`
+	Postgres    interface{} `json:"postgres,omitempty"`
+	Mongo       interface{} `json:"mongo,omitempty"`
+	Action      string      `json:"action"`
+	Error       string      `json:"error,omitempty"`
+}
+
+type ReconcileReport struct {
+	DryRun            bool             `json:"dry_run"`
+	StartedAt         time.Time        `json:"started_at"`
+	FinishedAt        time.Time        `json:"finished_at"`
+	ReferencesScanned int              `json:"references_scanned"`
+	DocumentsScanned  int              `json:"documents_scanned"`
+	SkippedPending    int              `json:"skipped_pending_outbox"`
+	Summary           map[string]int   `json:"summary"`
+	Issues            []ReconcileIssue `json:"issues"`
+}
+
+type ReconcileResponse struct {
+	Status string          `json:"status"`
+	Data   ReconcileReport `json:"data"`
+}
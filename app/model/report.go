@@ -17,6 +17,7 @@ type StatisticsResponse struct {
 	TotalPerPeriod   []StatisticItem           `json:"total_per_period"`
 	CompetitionLevel []StatisticItem           `json:"competition_levels"`
 	TopStudents      []StatisticTopStudentItem `json:"top_students"`
+	TotalPerProgram  []StatisticItem           `json:"total_per_study_program"`
 }
 
 type StudentAchievementReportResponse struct {
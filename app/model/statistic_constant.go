@@ -1,15 +1,10 @@
 package model
 
 var CompetitionLevelMap = map[string]string{
-	"local":    "Lokal",
-	"regional": "Regional",
-	"national": "Nasional",
-	"intl":     "Internasional",
-	"unknown":  "Tidak Diketahui",
-}
-
-var AchievementTypeMap = map[string]string{
-	"academic": "Akademik",
-	"nonacad":  "Non Akademik",
-	"unknown":  "Tidak Diketahui",
+	"local":         "Lokal",
+	"regional":      "Regional",
+	"national":      "Nasional",
+	"intl":          "Internasional",
+	"international": "Internasional",
+	"unknown":       "Tidak Diketahui",
 }
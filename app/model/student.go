@@ -2,21 +2,105 @@ package model
 
 import "time"
 
+// status akademik mahasiswa
+const (
+	AcademicStatusActive     = "active"
+	AcademicStatusLeave      = "leave"
+	AcademicStatusGraduated  = "graduated"
+	AcademicStatusDroppedOut = "dropped_out"
+	AcademicStatusInactive   = "inactive"
+)
+
+var AcademicStatusMap = map[string]string{
+	AcademicStatusActive:     "Aktif",
+	AcademicStatusLeave:      "Cuti",
+	AcademicStatusGraduated:  "Lulus",
+	AcademicStatusDroppedOut: "Drop Out",
+	AcademicStatusInactive:   "Non Aktif",
+}
+
 type Student struct {
-	ID        string  `json:"id"`
-	UserID    string  `json:"user_id"`
-	AdvisorID *string `json:"advisor_id"`
+	ID             string  `json:"id"`
+	UserID         string  `json:"user_id"`
+	FullName       string  `json:"full_name"`
+	NIM            string  `json:"nim"`
+	StudyProgramID *string `json:"study_program_id"`
+	StudyProgram   string  `json:"study_program"`
+	Faculty        string  `json:"faculty"`
+	EntryYear      int     `json:"entry_year"`
+	Semester       int     `json:"semester"`
+	AcademicStatus string  `json:"academic_status"`
+	AdvisorID      *string `json:"advisor_id"`
+}
+
+type StudentFilter struct {
+	NIM            string
+	StudyProgram   string
+	Faculty        string
+	EntryYear      int
+	Semester       int
+	AcademicStatus string
+	StudyProgramID string
+	// id unit yang boleh diakses, nil = semua
+	Scope []string
+}
+
+type StudentListResponse struct {
+	Status string    `json:"status"`
+	Data   []Student `json:"data"`
+}
+
+type StudentDetailResponse struct {
+	Status string  `json:"status"`
+	Data   Student `json:"data"`
+}
+
+type UpdateStudentRequest struct {
+	NIM            string `json:"nim" example:"434231023"`
+	StudyProgram   string `json:"study_program" example:"Teknik Informatika"`
+	Faculty        string `json:"faculty" example:"Fakultas Vokasi"`
+	EntryYear      int    `json:"entry_year" example:"2023"`
+	Semester       int    `json:"semester" example:"5"`
+	AcademicStatus string `json:"academic_status" example:"active"`
 }
 
 type AdviseeResponse struct {
-	ID        string  `json:"id"`
-	UserID    string  `json:"user_id"`
-	AdvisorID *string `json:"advisor_id"`
+	ID            string  `json:"id"`
+	UserID        string  `json:"user_id"`
+	AdvisorID     *string `json:"advisor_id"`
+	FullName      string  `json:"full_name"`
+	NIM           string  `json:"nim"`
+	StudyProgram  string  `json:"study_program"`
+	PendingCount  int     `json:"pending_count"`
+	VerifiedCount int     `json:"verified_count"`
+}
+
+type AdviseeListResponse struct {
+	Status string            `json:"status"`
+	Data   []AdviseeResponse `json:"data"`
 }
 
 type AssignAdvisorResponse struct {
-	Message   string `json:"message"`
-	AdvisorID string `json:"advisor_id"`
+	Message           string  `json:"message"`
+	AdvisorID         *string `json:"advisor_id"`
+	PreviousAdvisorID *string `json:"previous_advisor_id"`
+	// prestasi berstatus submitted ikut pindah ke antrean dosen wali baru
+	ReassignedPending int `json:"reassigned_pending"`
+}
+
+type AdvisorHistory struct {
+	ID           string     `json:"id"`
+	StudentID    string     `json:"student_id"`
+	LecturerID   string     `json:"lecturer_id"`
+	LecturerName string     `json:"lecturer_name"`
+	AssignedAt   time.Time  `json:"assigned_at"`
+	EndedAt      *time.Time `json:"ended_at"`
+	AssignedBy   *string    `json:"assigned_by"`
+}
+
+type AdvisorHistoryResponse struct {
+	Status string           `json:"status"`
+	Data   []AdvisorHistory `json:"data"`
 }
 
 type StudentAchievementResponse struct {
@@ -25,6 +109,56 @@ type StudentAchievementResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// advisor_id null / "" berarti melepas dosen wali
 type UpdateAdvisorRequest struct {
-	AdvisorID string `json:"advisor_id"`
-}
\ No newline at end of file
+	AdvisorID *string `json:"advisor_id"`
+}
+
+type AdvisorAssignment struct {
+	StudentID  string  `json:"student_id"`
+	LecturerID *string `json:"lecturer_id"`
+}
+
+// commit=false hanya preview, tidak ada data yang diubah
+type BulkAssignAdvisorRequest struct {
+	Assignments []AdvisorAssignment `json:"assignments"`
+	Commit      bool                `json:"commit"`
+}
+
+type AutoAssignAdvisorRequest struct {
+	StudyProgramID string `json:"study_program_id"`
+	MaxAdvisees    int    `json:"max_advisees" example:"30"`
+	Commit         bool   `json:"commit"`
+}
+
+type AdvisorAssignmentResult struct {
+	StudentID    string  `json:"student_id"`
+	StudentName  string  `json:"student_name"`
+	NIM          string  `json:"nim"`
+	LecturerID   *string `json:"lecturer_id"`
+	LecturerName string  `json:"lecturer_name"`
+	Error        string  `json:"error,omitempty"`
+}
+
+type LecturerLoad struct {
+	LecturerID   string `json:"lecturer_id"`
+	LecturerName string `json:"lecturer_name"`
+	Before       int    `json:"before"`
+	After        int    `json:"after"`
+}
+
+type BulkAssignAdvisorResponse struct {
+	Status    string                    `json:"status"`
+	Committed bool                      `json:"committed"`
+	Failed    int                       `json:"failed"`
+	Data      []AdvisorAssignmentResult `json:"data"`
+}
+
+type AutoAssignAdvisorResponse struct {
+	Status       string                    `json:"status"`
+	Committed    bool                      `json:"committed"`
+	MaxAdvisees  int                       `json:"max_advisees"`
+	Data         []AdvisorAssignmentResult `json:"data"`
+	Unassigned   []AdvisorAssignmentResult `json:"unassigned"`
+	LecturerLoad []LecturerLoad            `json:"lecturer_load"`
+}
@@ -1,14 +1,15 @@
 package model
 
 type User struct {
-	ID        string `json:"id"`
-	Username  string `json:"username"`
-	Email     string `json:"email"`
-	Password  string `json:"-"`
-	FullName  string `json:"full_name"`
-	RoleID    string `json:"role_id"`
-	RoleName  string `json:"role_name,omitempty"`
-	IsActive  bool   `json:"is_active"`
+	ID        string  `json:"id"`
+	Username  string  `json:"username"`
+	Email     string  `json:"email"`
+	Password  string  `json:"-"`
+	FullName  string  `json:"full_name"`
+	RoleID    string  `json:"role_id"`
+	RoleName  string  `json:"role_name,omitempty"`
+	IsActive  bool    `json:"is_active"`
+	OrgUnitID *string `json:"org_unit_id"`
 }
 
 type GetUserResponse struct {
@@ -54,5 +55,7 @@ type UpdateRoleResult struct {
 	IsActive bool   `json:"is_active"`
 }
 
-
-
+type UploadAvatarResponse struct {
+	Status string            `json:"status"`
+	Data   map[string]string `json:"data"`
+}
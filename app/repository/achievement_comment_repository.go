@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"uas-prestasi/app/model"
+
+	"github.com/lib/pq"
+)
+
+type AchievementCommentRepository struct {
+	DB *sql.DB
+}
+
+func NewAchievementCommentRepository(db *sql.DB) *AchievementCommentRepository {
+	return &AchievementCommentRepository{DB: db}
+}
+
+// List mengembalikan komentar achievement secara kronologis (belum disusun jadi thread)
+func (r *AchievementCommentRepository) List(achievementID string, includeInternal bool) ([]model.AchievementComment, error) {
+	rows, err := r.DB.Query(`
+		SELECT c.id, c.achievement_id, c.parent_id, c.author_id, u.full_name, COALESCE(ro.name, ''),
+		       c.body, c.attachment_ref, c.internal, c.created_at
+		FROM achievement_comments c
+		JOIN users u ON u.id = c.author_id
+		LEFT JOIN roles ro ON ro.id = u.role_id
+		WHERE c.achievement_id = $1
+		  AND (c.internal = false OR $2)
+		ORDER BY c.created_at, c.id
+	`, achievementID, includeInternal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.AchievementComment{}
+	for rows.Next() {
+		var c model.AchievementComment
+		if err := rows.Scan(&c.ID, &c.AchievementID, &c.ParentID, &c.AuthorID, &c.AuthorName, &c.AuthorRole,
+			&c.Body, &c.AttachmentRef, &c.Internal, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+
+	return comments, rows.Err()
+}
+
+// GetThreadRoot mengembalikan id komentar teratas dan flag internal untuk
+// komentar yang dibalas; sql.ErrNoRows kalau bukan milik achievement ini
+func (r *AchievementCommentRepository) GetThreadRoot(achievementID, commentID string) (string, bool, error) {
+	var (
+		rootID   string
+		internal bool
+	)
+	err := r.DB.QueryRow(`
+		SELECT COALESCE(c.parent_id, c.id), c.internal
+		FROM achievement_comments c
+		WHERE c.id = $1 AND c.achievement_id = $2
+	`, commentID, achievementID).Scan(&rootID, &internal)
+	return rootID, internal, err
+}
+
+// Create menyimpan komentar, menandai thread terbaca untuk penulis, dan
+// mengirim notifikasi ke mahasiswa pemilik dan dosen walinya (kecuali penulis).
+// Komentar internal tidak menimbulkan notifikasi.
+func (r *AchievementCommentRepository) Create(c *model.AchievementComment) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO achievement_comments (achievement_id, parent_id, author_id, body, attachment_ref, internal)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, c.AchievementID, c.ParentID, c.AuthorID, c.Body, c.AttachmentRef, c.Internal).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		SELECT u.full_name, COALESCE(ro.name, '')
+		FROM users u
+		LEFT JOIN roles ro ON ro.id = u.role_id
+		WHERE u.id = $1
+	`, c.AuthorID).Scan(&c.AuthorName, &c.AuthorRole)
+	if err != nil {
+		return err
+	}
+
+	if err := markCommentsRead(tx, c.AchievementID, c.AuthorID, c.CreatedAt); err != nil {
+		return err
+	}
+
+	if !c.Internal {
+		var studentUserID, advisorUserID sql.NullString
+		err = tx.QueryRow(`
+			SELECT s.user_id, l.user_id
+			FROM achievement_references ar
+			JOIN students s ON s.id = ar.student_id
+			LEFT JOIN lecturers l ON l.id = s.advisor_id
+			WHERE ar.id = $1
+		`, c.AchievementID).Scan(&studentUserID, &advisorUserID)
+		if err != nil {
+			return err
+		}
+
+		for _, recipient := range []sql.NullString{studentUserID, advisorUserID} {
+			if !recipient.Valid || recipient.String == c.AuthorID {
+				continue
+			}
+			err := insertNotification(tx, model.Notification{
+				UserID:        recipient.String,
+				Type:          model.NotificationAchievementComment,
+				Title:         "Komentar baru pada prestasi",
+				Message:       c.AuthorName + " menambahkan komentar",
+				AchievementID: &c.AchievementID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// MarkRead mencatat bahwa user sudah membaca thread sampai readUntil, yaitu
+// created_at komentar terbaru yang benar-benar dikirim ke user. Komentar yang
+// masuk setelah List tetap terhitung belum dibaca.
+func (r *AchievementCommentRepository) MarkRead(achievementID, userID string, readUntil time.Time) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := markCommentsRead(tx, achievementID, userID, readUntil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// markCommentsRead tidak pernah memundurkan posisi baca
+func markCommentsRead(tx *sql.Tx, achievementID, userID string, readUntil time.Time) error {
+	_, err := tx.Exec(`
+		INSERT INTO achievement_comment_reads (achievement_id, user_id, last_read_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (achievement_id, user_id)
+		DO UPDATE SET last_read_at = GREATEST(achievement_comment_reads.last_read_at, EXCLUDED.last_read_at)
+	`, achievementID, userID, readUntil)
+	return err
+}
+
+// UnreadCounts menghitung komentar orang lain yang dibuat setelah user terakhir
+// membaca thread. Achievement tanpa komentar belum dibaca tidak ada di map.
+func (r *AchievementCommentRepository) UnreadCounts(userID string, achievementIDs []string, includeInternal bool) (map[string]int, error) {
+	counts := map[string]int{}
+	if len(achievementIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := r.DB.Query(`
+		SELECT c.achievement_id, COUNT(*)
+		FROM achievement_comments c
+		LEFT JOIN achievement_comment_reads cr
+		       ON cr.achievement_id = c.achievement_id AND cr.user_id = $1
+		WHERE c.achievement_id = ANY($2)
+		  AND c.author_id <> $1
+		  AND (c.internal = false OR $3)
+		  AND (cr.last_read_at IS NULL OR c.created_at > cr.last_read_at)
+		GROUP BY c.achievement_id
+	`, userID, pq.Array(achievementIDs), includeInternal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id    string
+			count int
+		)
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		counts[id] = count
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnreadCounts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM achievement_comments c[\s\S]+achievement_comment_reads cr[\s\S]+GROUP BY c.achievement_id`).
+		WithArgs("u1", sqlmock.AnyArg(), false).
+		WillReturnRows(sqlmock.NewRows([]string{"achievement_id", "count"}).AddRow("a1", 2))
+
+	counts, err := NewAchievementCommentRepository(db).UnreadCounts("u1", []string{"a1", "a2"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a1": 2}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// halaman kosong tidak perlu query
+	counts, err = NewAchievementCommentRepository(db).UnreadCounts("u1", nil, false)
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestMarkRead_StoresGivenPositionWithoutGoingBack(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	readUntil := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO achievement_comment_reads[\s\S]+GREATEST\(achievement_comment_reads.last_read_at, EXCLUDED.last_read_at\)`).
+		WithArgs("a1", "u1", readUntil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, NewAchievementCommentRepository(db).MarkRead("a1", "u1", readUntil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
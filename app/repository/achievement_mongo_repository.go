@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"uas-prestasi/app/model"
@@ -9,9 +10,16 @@ import (
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 )
 
+// ErrVersionConflict: dokumen ada tetapi versinya sudah berubah sejak dibaca
+var ErrVersionConflict = errors.New("achievement version conflict")
+
+// ErrFilterTooBroad: filter dokumen cocok dengan terlalu banyak prestasi
+var ErrFilterTooBroad = errors.New("achievement filter too broad")
+
 type AchievementMongoRepository struct {
 	Collection *mongo.Collection
 }
@@ -22,22 +30,33 @@ func NewAchievementMongoRepository(db *mongo.Database) *AchievementMongoReposito
 	}
 }
 
-func (r *AchievementMongoRepository) InsertDraft(achievement *model.Achievement) (string, error) {
+// UpsertDraft membuat dokumen draft dengan _id yang sudah ditentukan di Postgres.
+// Idempoten: kalau dokumen sudah ada (event outbox diproses ulang), tidak ada yang berubah.
+func (r *AchievementMongoRepository) UpsertDraft(id string, achievement *model.Achievement) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	achievement.CreatedAt = time.Now()
-	achievement.UpdatedAt = time.Now()
-	achievement.Attachments = []model.Attachment{}
-
-	result, err := r.Collection.InsertOne(ctx, achievement)
+	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	objectID := result.InsertedID.(primitive.ObjectID)
+	achievement.ID = ""
+	if achievement.Version == 0 {
+		achievement.Version = 1
+	}
+	if achievement.Attachments == nil {
+		achievement.Attachments = []model.Attachment{}
+	}
 
-	return objectID.Hex(), nil
+	_, err = r.Collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objID},
+		bson.M{"$setOnInsert": achievement},
+		options.Update().SetUpsert(true),
+	)
+
+	return err
 }
 
 func (r *AchievementMongoRepository) FindByID(id string) (bson.M, error) {
@@ -49,7 +68,8 @@ func (r *AchievementMongoRepository) FindByID(id string) (bson.M, error) {
 	filter := bson.M{"_id": objID}
 
 	var result bson.M
-	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
 	err = r.Collection.FindOne(ctx, filter).Decode(&result)
 	if err != nil {
@@ -60,6 +80,198 @@ func (r *AchievementMongoRepository) FindByID(id string) (bson.M, error) {
 }
 
 
+// FindByIDs mengambil banyak dokumen dalam satu query $in. Hasil di-key dengan
+// hex _id; id yang tidak valid atau tidak ditemukan tidak ada di map.
+// fields kosong berarti dokumen lengkap.
+func (r *AchievementMongoRepository) FindByIDs(ids []string, fields []string) (map[string]bson.M, error) {
+	result := map[string]bson.M{}
+
+	objIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objIDs = append(objIDs, objID)
+		}
+	}
+	if len(objIDs) == 0 {
+		return result, nil
+	}
+
+	opts := options.Find()
+	if len(fields) > 0 {
+		projection := bson.M{}
+		for _, f := range fields {
+			projection[f] = 1
+		}
+		opts.SetProjection(projection)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": objIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		if objID, ok := doc["_id"].(primitive.ObjectID); ok {
+			result[objID.Hex()] = doc
+		}
+	}
+
+	return result, cursor.Err()
+}
+
+// FindIDs mengembalikan hex _id dokumen yang cocok dengan filter. Hasilnya
+// dipakai sebagai batasan query Postgres, jadi scoping role tetap di Postgres.
+// Lebih dari limit dokumen cocok → ErrFilterTooBroad, daftar id tidak dikirim ke Postgres.
+func (r *AchievementMongoRepository) FindIDs(filter model.AchievementSearchFilter, limit int) ([]string, error) {
+	query := bson.M{}
+	if filter.AchievementType != "" {
+		query["achievementType"] = filter.AchievementType
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$all": filter.Tags}
+	}
+	if filter.CompetitionLevel != "" {
+		query["details.competitionLevel"] = filter.CompetitionLevel
+	}
+	// lewat text index, bukan regex yang harus memindai seluruh koleksi
+	if filter.Query != "" {
+		query["$text"] = bson.M{"$search": filter.Query}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetLimit(int64(limit) + 1)
+
+	cursor, err := r.Collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := []string{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID.Hex())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) > limit {
+		return nil, ErrFilterTooBroad
+	}
+	return ids, nil
+}
+
+const achievementTextIndex = "achievements_text"
+
+// EnsureTextIndex membuat text index untuk pencarian. Satu koleksi hanya boleh
+// punya satu text index, jadi index lama dengan nama yang sama dibiarkan.
+// default_language none: isi campuran Indonesia/Inggris, tanpa stemming & stop word.
+func (r *AchievementMongoRepository) EnsureTextIndex() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	keys := bson.D{
+		{Key: "title", Value: "text"},
+		{Key: "description", Value: "text"},
+		{Key: "tags", Value: "text"},
+	}
+	weights := bson.M{"title": 10, "tags": 5, "description": 2}
+	for _, f := range model.SearchableDetailFields {
+		keys = append(keys, bson.E{Key: "details." + f, Value: "text"})
+		weights["details."+f] = 3
+	}
+
+	_, err := r.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: keys,
+		Options: options.Index().
+			SetName(achievementTextIndex).
+			SetWeights(weights).
+			SetDefaultLanguage("none"),
+	})
+	return err
+}
+
+var searchProjection = bson.M{
+	"studentId":       1,
+	"achievementType": 1,
+	"title":           1,
+	"description":     1,
+	"tags":            1,
+	"details":         1,
+}
+
+// TextSearch mencari lewat text index, diurutkan dari skor relevansi tertinggi.
+// studentIDs nil = semua mahasiswa; skor ada di field "score".
+func (r *AchievementMongoRepository) TextSearch(query string, studentIDs []string, limit int) ([]bson.M, error) {
+	filter := bson.M{"$text": bson.M{"$search": query}}
+	if studentIDs != nil {
+		filter["studentId"] = bson.M{"$in": studentIDs}
+	}
+
+	projection := bson.M{"score": bson.M{"$meta": "textScore"}}
+	for k, v := range searchProjection {
+		projection[k] = v
+	}
+
+	opts := options.Find().
+		SetProjection(projection).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}).
+		SetLimit(int64(limit))
+
+	return r.findAll(filter, opts)
+}
+
+// FindRecent mengambil dokumen terbaru untuk pencarian toleran salah ketik
+// yang dinilai di aplikasi
+func (r *AchievementMongoRepository) FindRecent(studentIDs []string, limit int) ([]bson.M, error) {
+	filter := bson.M{}
+	if studentIDs != nil {
+		filter["studentId"] = bson.M{"$in": studentIDs}
+	}
+
+	opts := options.Find().
+		SetProjection(searchProjection).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+
+	return r.findAll(filter, opts)
+}
+
+func (r *AchievementMongoRepository) findAll(filter bson.M, opts *options.FindOptions) ([]bson.M, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []bson.M{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
 func (r *AchievementMongoRepository) UpdateByID(id string, payload map[string]interface{}) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -72,13 +284,15 @@ func (r *AchievementMongoRepository) UpdateByID(id string, payload map[string]in
 	_, err = r.Collection.UpdateOne(
 		ctx,
 		bson.M{"_id": objID},
-		bson.M{"$set": payload},
+		bson.M{"$set": payload, "$inc": bson.M{"version": 1}},
 	)
 
 	return err
 }
 
-func (r *AchievementMongoRepository) DeleteByID(id string) error {
+// UnsetFields menghapus field dari dokumen, dipakai rekonsiliasi untuk
+// membersihkan field lama yang sudah tidak dipakai
+func (r *AchievementMongoRepository) UnsetFields(id string, fields ...string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -87,28 +301,102 @@ func (r *AchievementMongoRepository) DeleteByID(id string) error {
 		return err
 	}
 
-	_, err = r.Collection.DeleteOne(ctx, bson.M{"_id": objID})
+	unset := bson.M{}
+	for _, field := range fields {
+		unset[field] = ""
+	}
+
+	_, err = r.Collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objID},
+		bson.M{
+			"$unset": unset,
+			"$set":   bson.M{"updatedAt": time.Now()},
+			"$inc":   bson.M{"version": 1},
+		},
+	)
+
 	return err
 }
 
-func (r *AchievementMongoRepository) AddAttachment(mongoID string, attachment model.Attachment) error {
+// UpdateByIDIfVersion sama dengan UpdateByID tetapi hanya kalau versi dokumen
+// masih sama dengan yang dibaca client. Mengembalikan versi baru.
+func (r *AchievementMongoRepository) UpdateByIDIfVersion(id string, version int, payload map[string]interface{}) (int, error) {
+	return r.updateIfVersion(id, version, bson.M{"$set": payload})
+}
+
+// AddAttachmentIfVersion menambah attachment dengan pengecekan versi
+func (r *AchievementMongoRepository) AddAttachmentIfVersion(id string, version int, attachment model.Attachment) (int, error) {
+	return r.updateIfVersion(id, version, bson.M{"$push": bson.M{"attachments": attachment}})
+}
+
+// BumpVersion menaikkan versi tanpa mengubah isi, dipakai submit supaya edit
+// yang membaca versi sebelum submit ditolak. Tidak dibatalkan kalau submit gagal,
+// jadi ETag tetap berubah walaupun status prestasi tidak berubah.
+func (r *AchievementMongoRepository) BumpVersion(id string, version int) (int, error) {
+	return r.updateIfVersion(id, version, bson.M{})
+}
+
+func (r *AchievementMongoRepository) updateIfVersion(id string, version int, update bson.M) (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	objID, err := primitive.ObjectIDFromHex(mongoID)
+	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	_, err = r.Collection.UpdateOne(
-		ctx,
-		bson.M{"_id": objID},
-		bson.M{"$push": bson.M{"attachments": attachment}},
-	)
+	update["$inc"] = bson.M{"version": 1}
+
+	res, err := r.Collection.UpdateOne(ctx, versionFilter(objID, version), update)
+	if err != nil {
+		return 0, err
+	}
+
+	if res.MatchedCount == 0 {
+		n, err := r.Collection.CountDocuments(ctx, bson.M{"_id": objID})
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			return 0, mongo.ErrNoDocuments
+		}
+		return 0, ErrVersionConflict
+	}
+
+	return version + 1, nil
+}
 
+// versionFilter: dokumen lama tanpa field version dianggap versi 0
+func versionFilter(objID primitive.ObjectID, version int) bson.M {
+	if version == 0 {
+		return bson.M{
+			"_id": objID,
+			"$or": bson.A{
+				bson.M{"version": 0},
+				bson.M{"version": bson.M{"$exists": false}},
+			},
+		}
+	}
+	return bson.M{"_id": objID, "version": version}
+}
+
+func (r *AchievementMongoRepository) DeleteByID(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Collection.DeleteOne(ctx, bson.M{"_id": objID})
 	return err
 }
 
+// UpdatePoints idempoten: versi (dan ETag) hanya naik kalau poinnya benar-benar
+// berubah, jadi replay event tidak mengubah apa-apa. mongo.ErrNoDocuments kalau
+// dokumennya belum ada supaya event outbox dicoba lagi setelah draft_created diproses
 func (r *AchievementMongoRepository) UpdatePoints(id string, points int) error {
     ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
     defer cancel()
@@ -118,12 +406,28 @@ func (r *AchievementMongoRepository) UpdatePoints(id string, points int) error {
         return err
     }
 
-    _, err = r.Collection.UpdateOne(
+    res, err := r.Collection.UpdateOne(
         ctx,
-        bson.M{"_id": objID},
-        bson.M{"$set": bson.M{"points": points, "updated_at": time.Now()}},
+        bson.M{"_id": objID, "points": bson.M{"$ne": points}},
+        bson.M{
+            "$set": bson.M{"points": points, "updatedAt": time.Now()},
+            "$inc": bson.M{"version": 1},
+        },
     )
-    return err
-}
-
+    if err != nil {
+        return err
+    }
+    if res.MatchedCount > 0 {
+        return nil
+    }
 
+    // tidak cocok: poin sudah sama, atau dokumennya memang belum ada
+    n, err := r.Collection.CountDocuments(ctx, bson.M{"_id": objID})
+    if err != nil {
+        return err
+    }
+    if n == 0 {
+        return mongo.ErrNoDocuments
+    }
+    return nil
+}
@@ -3,11 +3,12 @@ package repository
 import (
 	"database/sql"
 	"errors"
-	"strconv"
 	"time"
 	"uas-prestasi/app/model"
 	"fmt"
 	"strings"
+
+	"github.com/lib/pq"
 )
 
 type AchievementReferenceRepository struct {
@@ -18,70 +19,261 @@ func NewAchievementReferenceRepository(db *sql.DB) *AchievementReferenceReposito
 	return &AchievementReferenceRepository{DB: db}
 }
 
-func (r *AchievementReferenceRepository) InsertDraft(ref *model.AchievementReference) error {
+// InsertDraft menyimpan reference draft beserta event outbox pembuatan dokumen
+// Mongo dalam satu transaksi. Mengembalikan id event outbox.
+func (r *AchievementReferenceRepository) InsertDraft(ref *model.AchievementReference, actorUserID string, achievement *model.Achievement) (string, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO achievement_references
 		(student_id, mongo_achievement_id, status)
 		VALUES ($1, $2, $3)
+		RETURNING id
 	`
 
-	_, err := r.DB.Exec(
+	err = tx.QueryRow(
 		query,
 		ref.StudentID,
 		ref.MongoAchievementID,
 		ref.Status,
-	)
+	).Scan(&ref.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := recordStatusChange(tx, ref.ID, nil, ref.Status, actorUserID, nil); err != nil {
+		return "", err
+	}
+
+	eventID, err := insertOutboxEvent(tx, ref.ID, model.OutboxAchievementDraftCreated, model.DraftCreatedPayload{
+		MongoID:     ref.MongoAchievementID,
+		Achievement: *achievement,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return eventID, tx.Commit()
+}
+
+// lockStatus mengunci baris prestasi sampai transaksi selesai dan
+// mengembalikan status saat ini untuk dicatat sebagai from_status
+func lockStatus(tx *sql.Tx, id string) (string, error) {
+	var status string
+	err := tx.QueryRow(`
+		SELECT status FROM achievement_references WHERE id = $1 FOR UPDATE
+	`, id).Scan(&status)
+	return status, err
+}
 
+// recordStatusChange menambah baris di achievement_status_history (append-only),
+// selalu dipanggil di transaksi yang sama dengan perubahan status.
+// actorUserID kosong untuk perubahan oleh sistem (mis. rekonsiliasi dari CLI).
+func recordStatusChange(tx *sql.Tx, id string, from *string, to, actorUserID string, note *string) error {
+	var actor *string
+	if actorUserID != "" {
+		actor = &actorUserID
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO achievement_status_history (achievement_id, from_status, to_status, actor_id, note)
+		VALUES ($1, $2, $3, $4, $5)
+	`, id, from, to, actor, note)
 	return err
 }
 
-func (r *AchievementReferenceRepository) SubmitDraft(id string, studentID string) error {
-	query := `
+// SubmitDraft mengajukan draft atau mengajukan ulang prestasi yang ditolak.
+// Setiap pengajuan dicatat sebagai baris baru di achievement_submissions.
+// beforeCommit dijalankan setelah status berubah selama baris masih terkunci
+// (dipakai untuk menaikkan versi dokumen Mongo); kalau gagal, pengajuan dibatalkan.
+func (r *AchievementReferenceRepository) SubmitDraft(id string, studentID, actorUserID string, suggestedPoints, documentVersion int, beforeCommit func() error) (int, error) {
+	submit := model.AchievementTransitions[model.ActionSubmit]
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	from, err := lockStatus(tx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	var submittedAt time.Time
+	err = tx.QueryRow(`
 		UPDATE achievement_references
 		SET 
-			status = 'submitted',
+			status = $4,
 			submitted_at = NOW(),
+			suggested_points = $3,
+			awarded_points = NULL,
+			points_override_reason = NULL,
+			rejection_note = NULL,
+			revision_note = NULL,
+			review_started_at = NULL,
+			verified_at = NULL,
+			verified_by = NULL,
 			updated_at = NOW()
 		WHERE id = $1 
 		  AND student_id = $2
-		  AND status = 'draft'
-	`
+		  AND status = ANY($5)
+		RETURNING submitted_at
+	`, id, studentID, suggestedPoints, submit.To, pq.Array(submit.From)).Scan(&submittedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	var submissionNo int
+	err = tx.QueryRow(`
+		INSERT INTO achievement_submissions (achievement_id, submission_no, submitted_at, suggested_points, document_version)
+		SELECT $1, COALESCE(MAX(submission_no), 0) + 1, $2, $3, $4
+		FROM achievement_submissions
+		WHERE achievement_id = $1
+		RETURNING submission_no
+	`, id, submittedAt, suggestedPoints, documentVersion).Scan(&submissionNo)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := recordStatusChange(tx, id, &from, submit.To, actorUserID, nil); err != nil {
+		return 0, err
+	}
+
+	if beforeCommit != nil {
+		if err := beforeCommit(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return submissionNo, nil
+}
 
-	res, err := r.DB.Exec(query, id, studentID)
+// MarkReviewStarted menandai dosen wali sudah mulai memeriksa pengajuan,
+// setelah itu mahasiswa tidak bisa menarik kembali pengajuannya
+func (r *AchievementReferenceRepository) MarkReviewStarted(id string) error {
+	_, err := r.DB.Exec(`
+		UPDATE achievement_references
+		SET review_started_at = NOW()
+		WHERE id = $1 AND status = $2 AND review_started_at IS NULL
+	`, id, model.StatusSubmitted)
+	return err
+}
+
+// Withdraw menarik pengajuan yang belum mulai diperiksa kembali menjadi draft,
+// menutup siklus pengajuan sebagai "withdrawn", dan memberi notifikasi ke dosen wali
+func (r *AchievementReferenceRepository) Withdraw(id, studentID, actorUserID string) error {
+	withdraw := model.AchievementTransitions[model.ActionWithdraw]
+
+	tx, err := r.DB.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := res.RowsAffected()
+	from, err := lockStatus(tx, id)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(`
+		UPDATE achievement_references
+		SET status = $3, suggested_points = NULL, updated_at = NOW()
+		WHERE id = $1
+		  AND student_id = $2
+		  AND status = ANY($4)
+		  AND review_started_at IS NULL
+	`, id, studentID, withdraw.To, pq.Array(withdraw.From))
 	if err != nil {
 		return err
 	}
 
+	rows, _ := res.RowsAffected()
 	if rows == 0 {
 		return sql.ErrNoRows
 	}
 
-	return nil
+	if err := closeSubmission(tx, id, "withdrawn", actorUserID, nil); err != nil {
+		return err
+	}
+
+	if err := recordStatusChange(tx, id, &from, withdraw.To, actorUserID, nil); err != nil {
+		return err
+	}
+
+	var advisorUserID, studentName string
+	err = tx.QueryRow(`
+		SELECT l.user_id, COALESCE(u.full_name, '')
+		FROM students s
+		JOIN lecturers l ON l.id = s.advisor_id
+		LEFT JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1
+	`, studentID).Scan(&advisorUserID, &studentName)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	// mahasiswa tanpa dosen wali tidak perlu notifikasi
+	if err == nil {
+		err = insertNotification(tx, model.Notification{
+			UserID:        advisorUserID,
+			Type:          model.NotificationAchievementWithdrawn,
+			Title:         "Pengajuan prestasi ditarik",
+			Message:       studentName + " menarik kembali pengajuan prestasi untuk diperbaiki",
+			AchievementID: &id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// closeSubmission mengisi keputusan dosen pada pengajuan yang masih terbuka,
+// note berisi catatan penolakan atau permintaan revisi.
+// actorUserID kosong untuk keputusan oleh sistem (rekonsiliasi).
+func closeSubmission(tx *sql.Tx, id, decision, actorUserID string, note *string) error {
+	var actor *string
+	if actorUserID != "" {
+		actor = &actorUserID
+	}
+
+	_, err := tx.Exec(`
+		UPDATE achievement_submissions
+		SET decision = $2, decided_at = NOW(), decided_by = $3, note = $4
+		WHERE achievement_id = $1 AND decision IS NULL
+	`, id, decision, actor, note)
+	return err
 }
 
 func (r *AchievementReferenceRepository) GetForVerification(refID string, lecturerUserID string) (*model.AchievementReference, error) {
 	query := `
-		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.suggested_points
 		FROM achievement_references ar
 		JOIN students s ON s.id = ar.student_id
 		JOIN lecturers l ON s.advisor_id = l.id
 		WHERE ar.id = $1
-		  AND ar.status = 'submitted'
+		  AND ar.status = $3
 		  AND l.user_id = $2
 	`
 
 	var ref model.AchievementReference
 
-	err := r.DB.QueryRow(query, refID, lecturerUserID).Scan(
+	err := r.DB.QueryRow(query, refID, lecturerUserID, model.StatusSubmitted).Scan(
 		&ref.ID,
 		&ref.StudentID,
 		&ref.MongoAchievementID,
 		&ref.Status,
+		&ref.SuggestedPoints,
 	)
 
 	if err != nil {
@@ -91,45 +283,137 @@ func (r *AchievementReferenceRepository) GetForVerification(refID string, lectur
 	return &ref, nil
 }
 
-func (r *AchievementReferenceRepository) Verify(id, lecturerID string) error {
+// Verify mengubah status dan menulis event outbox untuk poin di Mongo
+// dalam satu transaksi. Mengembalikan id event outbox.
+func (r *AchievementReferenceRepository) Verify(id, lecturerID string, awardedPoints int, overrideReason *string) (string, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	from, err := lockStatus(tx, id)
+	if err != nil {
+		return "", err
+	}
+
+	verify := model.AchievementTransitions[model.ActionVerify]
+
 	query := `
 		UPDATE achievement_references
 		SET 
-			status = 'verified',
+			status = $5,
 			verified_at = NOW(),
 			verified_by = $1,
+			awarded_points = $3,
+			points_override_reason = $4,
 			updated_at = NOW()
 		WHERE id = $2 
-		  AND status = 'submitted'
+		  AND status = ANY($6)
+		RETURNING mongo_achievement_id
 	`
 
-	res, err := r.DB.Exec(query, lecturerID, id)
+	var mongoID string
+	err = tx.QueryRow(query, lecturerID, id, awardedPoints, overrideReason, verify.To, pq.Array(verify.From)).Scan(&mongoID)
+	if err == sql.ErrNoRows {
+		return "", errors.New("data tidak ditemukan atau belum diajukan")
+	}
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
-		return errors.New("data tidak ditemukan atau belum diajukan")
+	if err := closeSubmission(tx, id, verify.To, lecturerID, nil); err != nil {
+		return "", err
 	}
 
-	return nil
+	if err := recordStatusChange(tx, id, &from, verify.To, lecturerID, overrideReason); err != nil {
+		return "", err
+	}
+
+	eventID, err := insertOutboxEvent(tx, id, model.OutboxAchievementPointsAwarded, model.PointsAwardedPayload{
+		MongoID: mongoID,
+		Points:  awardedPoints,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return eventID, tx.Commit()
 }
 
 func (r *AchievementReferenceRepository) Reject(id, lecturerID, note string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	from, err := lockStatus(tx, id)
+	if err != nil {
+		return err
+	}
+
+	reject := model.AchievementTransitions[model.ActionReject]
+
 	query := `
 		UPDATE achievement_references
 		SET 
-			status = 'rejected',
+			status = $4,
 			rejection_note = $1,
 			verified_at = NOW(),
 			verified_by = $2,
 			updated_at = NOW()
 		WHERE id = $3 
-		  AND status = 'submitted'
+		  AND status = ANY($5)
 	`
 
-	res, err := r.DB.Exec(query, note, lecturerID, id)
+	res, err := tx.Exec(query, note, lecturerID, id, reject.To, pq.Array(reject.From))
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return errors.New("data tidak ditemukan atau belum diajukan")
+	}
+
+	if err := closeSubmission(tx, id, reject.To, lecturerID, &note); err != nil {
+		return err
+	}
+
+	if err := recordStatusChange(tx, id, &from, reject.To, lecturerID, &note); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RequestRevision mengembalikan prestasi ke mahasiswa untuk dilengkapi.
+// Bukan penolakan: tidak dihitung sebagai rejected dan tidak memakai jatah pengajuan ulang.
+// verified_at/verified_by tidak diisi; keputusan dosen tercatat di achievement_submissions.
+func (r *AchievementReferenceRepository) RequestRevision(id, lecturerID, note string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	from, err := lockStatus(tx, id)
+	if err != nil {
+		return err
+	}
+
+	revision := model.AchievementTransitions[model.ActionRequestRevision]
+
+	res, err := tx.Exec(`
+		UPDATE achievement_references
+		SET 
+			status = $3,
+			revision_note = $1,
+			updated_at = NOW()
+		WHERE id = $2 
+		  AND status = ANY($4)
+	`, note, id, revision.To, pq.Array(revision.From))
 	if err != nil {
 		return err
 	}
@@ -139,14 +423,23 @@ func (r *AchievementReferenceRepository) Reject(id, lecturerID, note string) err
 		return errors.New("data tidak ditemukan atau belum diajukan")
 	}
 
-	return nil
+	if err := closeSubmission(tx, id, revision.To, lecturerID, &note); err != nil {
+		return err
+	}
+
+	if err := recordStatusChange(tx, id, &from, revision.To, lecturerID, &note); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // help buat pagination
-func sanitizeSort(sortBy, order string) (string, string) {
+func SanitizeSort(sortBy, order string) (string, string) {
 	allowedSort := map[string]bool{
-		"created_at": true,
-		"status":     true,
+		"created_at":   true,
+		"submitted_at": true,
+		"status":       true,
 	}
 
 	if !allowedSort[sortBy] {
@@ -160,41 +453,133 @@ func sanitizeSort(sortBy, order string) (string, string) {
 	return sortBy, order
 }
 
-func (r *AchievementReferenceRepository) ListByStudent(
-	studentID string,
-	limit, offset int,
-	sortBy, order, status string,
-) ([]model.AchievementReference, int, error) {
+// listFilterConditions menerjemahkan filter ke kondisi WHERE untuk alias ar,
+// placeholder dimulai dari argIndex
+func listFilterConditions(filter model.AchievementListFilter, argIndex int) ([]string, []interface{}) {
+	where := []string{}
+	args := []interface{}{}
 
-	sortBy, order = sanitizeSort(sortBy, order)
+	add := func(cond string, arg interface{}) {
+		where = append(where, fmt.Sprintf(cond, argIndex))
+		args = append(args, arg)
+		argIndex++
+	}
 
-	baseQuery := `
-		FROM achievement_references
-		WHERE student_id = $1
-	`
-	args := []interface{}{studentID}
+	if filter.Status != "" {
+		add("ar.status = $%d", filter.Status)
+	}
+	if filter.StudentID != "" {
+		add("ar.student_id = $%d", filter.StudentID)
+	}
+	if filter.AdvisorID != "" {
+		add("ar.student_id IN (SELECT id FROM students WHERE advisor_id = $%d)", filter.AdvisorID)
+	}
+	if filter.CreatedFrom != nil {
+		add("ar.created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		add("ar.created_at < $%d", *filter.CreatedTo)
+	}
+	if filter.SubmittedFrom != nil {
+		add("ar.submitted_at >= $%d", *filter.SubmittedFrom)
+	}
+	if filter.SubmittedTo != nil {
+		add("ar.submitted_at < $%d", *filter.SubmittedTo)
+	}
+	// verified_at juga diisi saat ditolak, jadi status ikut dibatasi
+	if filter.VerifiedFrom != nil || filter.VerifiedTo != nil {
+		add("ar.status = $%d", model.StatusVerified)
+	}
+	if filter.VerifiedFrom != nil {
+		add("ar.verified_at >= $%d", *filter.VerifiedFrom)
+	}
+	if filter.VerifiedTo != nil {
+		add("ar.verified_at < $%d", *filter.VerifiedTo)
+	}
+	if filter.PointsMin != nil {
+		add("ar.awarded_points >= $%d", *filter.PointsMin)
+	}
+	if filter.PointsMax != nil {
+		add("ar.awarded_points <= $%d", *filter.PointsMax)
+	}
+	if filter.MongoIDs != nil {
+		add("ar.mongo_achievement_id = ANY($%d)", pq.Array(filter.MongoIDs))
+	}
+
+	return where, args
+}
+
+// ErrInvalidCursor: cursor tidak cocok dengan sort/order list
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// keysetCondition memilih baris setelah cursor dengan urutan
+// "kolom <order> NULLS LAST, id <order>"
+func keysetCondition(col, order string, after *model.ListCursor, argIndex int) (string, []interface{}) {
+	op := "<"
+	if order == "asc" {
+		op = ">"
+	}
+
+	if after.Value == nil {
+		return fmt.Sprintf("(ar.%s IS NULL AND ar.id %s $%d)", col, op, argIndex),
+			[]interface{}{after.ID}
+	}
+
+	return fmt.Sprintf("(ar.%[1]s %[2]s $%[3]d OR (ar.%[1]s = $%[3]d AND ar.id %[2]s $%[4]d) OR ar.%[1]s IS NULL)",
+			col, op, argIndex, argIndex+1),
+		[]interface{}{*after.Value, after.ID}
+}
+
+// listPage menjalankan query list dengan pagination offset atau cursor.
+// Mode cursor mengembalikan sampai Limit+1 baris supaya pemanggil tahu
+// masih ada halaman berikutnya; total hanya dihitung kalau diminta.
+func (r *AchievementReferenceRepository) listPage(
+	selectCols, baseQuery string,
+	where []string,
+	args []interface{},
+	page model.PageRequest,
+	dest func(ref *model.AchievementReference) []interface{},
+) ([]model.AchievementReference, int, error) {
 
-	if status != "" {
-		baseQuery += " AND status = $2"
-		args = append(args, status)
+	sortBy, order := SanitizeSort(page.SortBy, page.Order)
+	if page.After != nil && (page.After.Sort != sortBy || page.After.Order != order) {
+		return nil, 0, ErrInvalidCursor
+	}
+
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = " WHERE " + strings.Join(where, " AND ")
 	}
 
 	// count
-	countQuery := "SELECT COUNT(*) " + baseQuery
-	var total int
-	if err := r.DB.QueryRow(countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, err
+	total := 0
+	if !page.Cursor || page.CountTotal {
+		countQuery := "SELECT COUNT(*) " + baseQuery + whereSQL
+		if err := r.DB.QueryRow(countQuery, args...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if page.After != nil {
+		cond, keyArgs := keysetCondition(sortBy, order, page.After, len(args)+1)
+		where = append(where, cond)
+		args = append(args, keyArgs...)
+		whereSQL = " WHERE " + strings.Join(where, " AND ")
 	}
 
 	// data
 	dataQuery := `
-		SELECT id, student_id, status, mongo_achievement_id
-	` + baseQuery + `
-		ORDER BY ` + sortBy + ` ` + order + `
-		LIMIT $` + strconv.Itoa(len(args)+1) + `
-		OFFSET $` + strconv.Itoa(len(args)+2)
+		SELECT ` + selectCols + `, ar.` + sortBy + `::text
+	` + baseQuery + whereSQL + fmt.Sprintf(`
+		ORDER BY ar.%[1]s %[2]s NULLS LAST, ar.id %[2]s`, sortBy, order)
 
-	args = append(args, limit, offset)
+	if page.Cursor {
+		dataQuery += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, page.Limit+1)
+	} else {
+		dataQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, page.Limit, page.Offset)
+	}
 
 	rows, err := r.DB.Query(dataQuery, args...)
 	if err != nil {
@@ -202,126 +587,163 @@ func (r *AchievementReferenceRepository) ListByStudent(
 	}
 	defer rows.Close()
 
-	var results []model.AchievementReference
+	results := []model.AchievementReference{}
 	for rows.Next() {
 		var ref model.AchievementReference
-		rows.Scan(&ref.ID, &ref.StudentID, &ref.Status, &ref.MongoAchievementID)
+		if err := rows.Scan(append(dest(&ref), &ref.SortKey)...); err != nil {
+			return nil, 0, err
+		}
 		results = append(results, ref)
 	}
 
-	return results, total, nil
+	return results, total, rows.Err()
 }
 
+func basicRefDest(ref *model.AchievementReference) []interface{} {
+	return []interface{}{&ref.ID, &ref.StudentID, &ref.Status, &ref.MongoAchievementID}
+}
+
+func (r *AchievementReferenceRepository) ListByStudent(
+	studentID string,
+	page model.PageRequest,
+	filter model.AchievementListFilter,
+) ([]model.AchievementReference, int, error) {
+
+	conds, args := listFilterConditions(filter, 2)
+	where := append([]string{"ar.student_id = $1"}, conds...)
+	args = append([]interface{}{studentID}, args...)
+
+	return r.listPage(
+		"ar.id, ar.student_id, ar.status, ar.mongo_achievement_id",
+		"FROM achievement_references ar",
+		where, args, page, basicRefDest,
+	)
+}
+
+// ListByLecturer menampilkan antrean prestasi mahasiswa bimbingan dosen.
+// Relasi dosen wali lewat students.advisor_id → lecturers.id, draft & deleted tidak ikut.
 func (r *AchievementReferenceRepository) ListByLecturer(
 	lecturerUserID string,
-	limit, offset int,
-	sortBy, order, status string,
+	page model.PageRequest,
+	filter model.AchievementListFilter,
 ) ([]model.AchievementReference, int, error) {
 
-	sortBy, order = sanitizeSort(sortBy, order)
-
 	baseQuery := `
 		FROM achievement_references ar
 		JOIN students s ON s.id = ar.student_id
-		WHERE s.advisor_user_id = $1
+		JOIN lecturers l ON s.advisor_id = l.id
+		LEFT JOIN users u ON u.id = s.user_id
 	`
-	args := []interface{}{lecturerUserID}
+	conds, args := listFilterConditions(filter, 2)
+	where := append([]string{
+		"l.user_id = $1",
+		"ar.status NOT IN ('draft', 'deleted')",
+	}, conds...)
+	args = append([]interface{}{lecturerUserID}, args...)
+
+	return r.listPage(`
+		ar.id, ar.student_id, ar.status, ar.mongo_achievement_id,
+		ar.submitted_at, ar.created_at, COALESCE(u.full_name, '')`,
+		baseQuery, where, args, page,
+		func(ref *model.AchievementReference) []interface{} {
+			return []interface{}{
+				&ref.ID, &ref.StudentID, &ref.Status, &ref.MongoAchievementID,
+				&ref.SubmittedAt, &ref.CreatedAt, &ref.StudentName,
+			}
+		},
+	)
+}
 
-	if status != "" {
-		baseQuery += " AND ar.status = $2"
-		args = append(args, status)
-	}
+// scope = id unit organisasi yang boleh dilihat, nil = semua
+func (r *AchievementReferenceRepository) ListAll(page model.PageRequest, filter model.AchievementListFilter, scope []string) ([]model.AchievementReference, int, error) {
+	where, args := listFilterConditions(filter, 1)
 
-	// count
-	countQuery := "SELECT COUNT(*) " + baseQuery
-	var total int
-	if err := r.DB.QueryRow(countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, err
+	if scope != nil {
+		where = append(where, fmt.Sprintf(
+			"ar.student_id IN (SELECT id FROM students WHERE study_program_id = ANY($%d))", len(args)+1))
+		args = append(args, pq.Array(scope))
 	}
 
-	// data
-	dataQuery := `
-		SELECT ar.id, ar.student_id, ar.status, ar.mongo_achievement_id
-	` + baseQuery + `
-		ORDER BY ` + sortBy + ` ` + order + `
-		LIMIT $` + strconv.Itoa(len(args)+1) + `
-		OFFSET $` + strconv.Itoa(len(args)+2)
-
-	args = append(args, limit, offset)
+	return r.listPage(
+		"ar.id, ar.student_id, ar.status, ar.mongo_achievement_id",
+		"FROM achievement_references ar",
+		where, args, page, basicRefDest,
+	)
+}
 
-	rows, err := r.DB.Query(dataQuery, args...)
+// GetByMongoIDs dipakai pencarian untuk mengecek status reference hasil Mongo
+func (r *AchievementReferenceRepository) GetByMongoIDs(mongoIDs []string) (map[string]model.AchievementReference, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, student_id, status, mongo_achievement_id
+		FROM achievement_references
+		WHERE mongo_achievement_id = ANY($1)
+	`, pq.Array(mongoIDs))
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	var results []model.AchievementReference
+	result := map[string]model.AchievementReference{}
 	for rows.Next() {
 		var ref model.AchievementReference
-		rows.Scan(&ref.ID, &ref.StudentID, &ref.Status, &ref.MongoAchievementID)
-		results = append(results, ref)
+		if err := rows.Scan(&ref.ID, &ref.StudentID, &ref.Status, &ref.MongoAchievementID); err != nil {
+			return nil, err
+		}
+		result[ref.MongoAchievementID] = ref
 	}
 
-	return results, total, nil
+	return result, rows.Err()
 }
 
-func (r *AchievementReferenceRepository) ListAll(limit, offset int, sortBy, order, status string) ([]model.AchievementReference, int, error) {
-	sortBy, order = sanitizeSort(sortBy, order)
-
-	baseQuery := `FROM achievement_references`
-	where := []string{}
-	args := []interface{}{}
-	argIndex := 1
-
-	if status != "" {
-		where = append(where, fmt.Sprintf("status = $%d", argIndex))
-		args = append(args, status)
-		argIndex++
-	}
-
-	whereSQL := ""
-	if len(where) > 0 {
-		whereSQL = " WHERE " + strings.Join(where, " AND ")
-	}
-
-	// ===== Count =====
-	countQuery := "SELECT COUNT(*) " + baseQuery + whereSQL
-	var total int
-	if err := r.DB.QueryRow(countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, err
-	}
+// GetAdviseeStudentIDs mengembalikan id mahasiswa bimbingan dosen (by user id)
+func (r *AchievementReferenceRepository) GetAdviseeStudentIDs(lecturerUserID string) ([]string, error) {
+	return r.queryStudentIDs(`
+		SELECT s.id FROM students s
+		JOIN lecturers l ON s.advisor_id = l.id
+		WHERE l.user_id = $1
+	`, lecturerUserID)
+}
 
-	// ===== Data =====
-	dataQuery := `
-		SELECT id, student_id, status, mongo_achievement_id
-	` + baseQuery + whereSQL + fmt.Sprintf(`
-		ORDER BY %s %s
-		LIMIT $%d OFFSET $%d`, sortBy, order, argIndex, argIndex+1)
+// GetStudentIDsInPrograms mengembalikan id mahasiswa di program studi scope
+func (r *AchievementReferenceRepository) GetStudentIDsInPrograms(scope []string) ([]string, error) {
+	return r.queryStudentIDs(`
+		SELECT id FROM students WHERE study_program_id = ANY($1)
+	`, pq.Array(scope))
+}
 
-	args = append(args, limit, offset)
+// GetStudentProgramID mengembalikan program studi mahasiswa (nil kalau belum ditempatkan)
+func (r *AchievementReferenceRepository) GetStudentProgramID(studentID string) (*string, error) {
+	var programID *string
+	err := r.DB.QueryRow(`
+		SELECT study_program_id FROM students WHERE id = $1
+	`, studentID).Scan(&programID)
+	return programID, err
+}
 
-	rows, err := r.DB.Query(dataQuery, args...)
+func (r *AchievementReferenceRepository) queryStudentIDs(query string, args ...interface{}) ([]string, error) {
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	var results []model.AchievementReference
+	ids := []string{}
 	for rows.Next() {
-		var ref model.AchievementReference
-		if err := rows.Scan(&ref.ID, &ref.StudentID, &ref.Status, &ref.MongoAchievementID); err != nil {
-			return nil, 0, err
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
 		}
-		results = append(results, ref)
+		ids = append(ids, id)
 	}
 
-	return results, total, nil
+	return ids, rows.Err()
 }
 
 // detail achievement
 func (r *AchievementReferenceRepository) GetByID(id string) (*model.AchievementReference, error) {
 	query := `
-		SELECT id, student_id, mongo_achievement_id, status, created_at
+		SELECT id, student_id, mongo_achievement_id, status, created_at,
+		       suggested_points, awarded_points, points_override_reason
 		FROM achievement_references
 		WHERE id = $1
 	`
@@ -333,6 +755,9 @@ func (r *AchievementReferenceRepository) GetByID(id string) (*model.AchievementR
 		&a.MongoAchievementID,
 		&a.Status,
 		&a.CreatedAt,
+		&a.SuggestedPoints,
+		&a.AwardedPoints,
+		&a.PointsOverride,
 	)
 
 	if err != nil {
@@ -342,21 +767,26 @@ func (r *AchievementReferenceRepository) GetByID(id string) (*model.AchievementR
 	return &a, nil
 }
 
-func (r *AchievementReferenceRepository) GetDraftByOwner(id, studentID string) (*model.AchievementReference, error) {
+// GetEditableByOwner mengambil prestasi milik mahasiswa yang masih boleh diubah
+// (draft, ditolak, atau diminta revisi) beserta jumlah penolakannya
+func (r *AchievementReferenceRepository) GetEditableByOwner(id, studentID string) (*model.AchievementReference, error) {
 	query := `
-		SELECT id, mongo_achievement_id, status
-		FROM achievement_references
-		WHERE id = $1 
-		  AND student_id = $2
-		  AND status = 'draft'
+		SELECT ar.id, ar.mongo_achievement_id, ar.status,
+		       (SELECT COUNT(*) FROM achievement_submissions sub
+		        WHERE sub.achievement_id = ar.id AND sub.decision = $3)
+		FROM achievement_references ar
+		WHERE ar.id = $1 
+		  AND ar.student_id = $2
+		  AND ar.status = ANY($4)
 	`
 
 	var ref model.AchievementReference
 
-	err := r.DB.QueryRow(query, id, studentID).Scan(
+	err := r.DB.QueryRow(query, id, studentID, model.StatusRejected, pq.Array(model.EditableStatuses())).Scan(
 		&ref.ID,
 		&ref.MongoAchievementID,
 		&ref.Status,
+		&ref.RejectionCount,
 	)
 
 	if err != nil {
@@ -366,14 +796,27 @@ func (r *AchievementReferenceRepository) GetDraftByOwner(id, studentID string) (
 	return &ref, nil
 }
 
-func (r *AchievementReferenceRepository) DeleteDraft(id, studentID string) error {
-	res, err := r.DB.Exec(`
+func (r *AchievementReferenceRepository) DeleteDraft(id, studentID, actorUserID string) error {
+	del := model.AchievementTransitions[model.ActionDelete]
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	from, err := lockStatus(tx, id)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(`
 		UPDATE achievement_references
-		SET status = 'deleted', updated_at = NOW()
+		SET status = $3, updated_at = NOW()
 		WHERE id = $1
 		  AND student_id = $2
-		  AND status = 'draft'
-	`, id, studentID)
+		  AND status = ANY($4)
+	`, id, studentID, del.To, pq.Array(del.From))
 
 	if err != nil {
 		return err
@@ -384,7 +827,11 @@ func (r *AchievementReferenceRepository) DeleteDraft(id, studentID string) error
 		return errors.New("draft not deletable")
 	}
 
-	return nil
+	if err := recordStatusChange(tx, id, &from, del.To, actorUserID, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *AchievementReferenceRepository) GetStudentIDByUser(userID string) (string, error) {
@@ -419,63 +866,56 @@ func (r *AchievementReferenceRepository) IsAdvisorOf(userID string, studentID st
 	return count > 0, nil
 }
 
-func (r *AchievementReferenceRepository) GetHistory(id string) ([]map[string]interface{}, error) {
-	query := `
-		SELECT status, created_at, submitted_at, verified_at, rejection_note, verified_by
-		FROM achievement_references
-		WHERE id = $1
-	`
-
-	var status string
-	var createdAt, submittedAt, verifiedAt sql.NullTime
-	var rejectionNote, verifiedBy sql.NullString
-
-	err := r.DB.QueryRow(query, id).Scan(
-		&status,
-		&createdAt,
-		&submittedAt,
-		&verifiedAt,
-		&rejectionNote,
-		&verifiedBy,
-	)
+// GetHistory membaca achievement_status_history secara kronologis
+func (r *AchievementReferenceRepository) GetHistory(id string) ([]model.HistoryItem, error) {
+	rows, err := r.DB.Query(`
+		SELECT COALESCE(from_status, ''), to_status, changed_at,
+		       COALESCE(actor_id::text, ''), COALESCE(note, '')
+		FROM achievement_status_history
+		WHERE achievement_id = $1
+		ORDER BY changed_at, id
+	`, id)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	history := []map[string]interface{}{}
-
-	// Draft (selalu ada)
-	if createdAt.Valid {
-		history = append(history, map[string]interface{}{
-			"status": "draft",
-			"time":   createdAt.Time,
-		})
+	history := []model.HistoryItem{}
+	for rows.Next() {
+		var h model.HistoryItem
+		if err := rows.Scan(&h.FromStatus, &h.Status, &h.UpdatedAt, &h.UpdatedBy, &h.Note); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
 	}
 
-	// Submitted
-	if submittedAt.Valid {
-		history = append(history, map[string]interface{}{
-			"status": "submitted",
-			"time":   submittedAt.Time,
-		})
+	return history, rows.Err()
+}
+
+// GetSubmissions membaca semua pengajuan achievement, urut nomor pengajuan
+func (r *AchievementReferenceRepository) GetSubmissions(id string) ([]model.AchievementSubmission, error) {
+	rows, err := r.DB.Query(`
+		SELECT submission_no, submitted_at, document_version, decision, decided_at, note
+		FROM achievement_submissions
+		WHERE achievement_id = $1
+		ORDER BY submission_no
+	`, id)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	// Verified / Rejected
-	if status == "verified" && verifiedAt.Valid {
-		history = append(history, map[string]interface{}{
-			"status":      "verified",
-			"time":        verifiedAt.Time,
-			"verified_by": verifiedBy.String,
-		})
-	} else if status == "rejected" && verifiedAt.Valid {
-		history = append(history, map[string]interface{}{
-			"status": "rejected",
-			"time":   verifiedAt.Time,
-			"note":   rejectionNote.String,
-		})
+	submissions := []model.AchievementSubmission{}
+	for rows.Next() {
+		var sub model.AchievementSubmission
+		if err := rows.Scan(&sub.SubmissionNo, &sub.SubmittedAt, &sub.DocumentVersion,
+			&sub.Decision, &sub.DecidedAt, &sub.Note); err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, sub)
 	}
 
-	return history, nil
+	return submissions, rows.Err()
 }
 
 func (r *StudentRepository) GetAchievements(studentID string) ([]map[string]interface{}, error) {
@@ -511,7 +951,7 @@ func (r *StudentRepository) GetAchievements(studentID string) ([]map[string]inte
 
 func (r *AchievementReferenceRepository) GetOwnedAchievement(id, studentID string) (*model.AchievementReference, error) {
 	query := `
-		SELECT id, student_id, mongo_achievement_id, status, created_at
+		SELECT id, student_id, mongo_achievement_id, status, created_at, review_started_at
 		FROM achievement_references
 		WHERE id = $1 AND student_id = $2
 	`
@@ -523,6 +963,7 @@ func (r *AchievementReferenceRepository) GetOwnedAchievement(id, studentID strin
 		&ref.MongoAchievementID,
 		&ref.Status,
 		&ref.CreatedAt,
+		&ref.ReviewStartedAt,
 	)
 
 	if err != nil {
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"uas-prestasi/app/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmitDraft_BeforeCommitFailureRollsBack(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM achievement_references WHERE id = \$1 FOR UPDATE`).
+		WithArgs("a1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StatusDraft))
+	mock.ExpectQuery(`UPDATE achievement_references`).
+		WillReturnRows(sqlmock.NewRows([]string{"submitted_at"}).AddRow(time.Now()))
+	mock.ExpectQuery(`INSERT INTO achievement_submissions`).
+		WillReturnRows(sqlmock.NewRows([]string{"submission_no"}).AddRow(1))
+	mock.ExpectExec(`INSERT INTO achievement_status_history`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// versi Mongo gagal dinaikkan → status tidak jadi berubah
+	mock.ExpectRollback()
+
+	repo := NewAchievementReferenceRepository(db)
+	_, err := repo.SubmitDraft("a1", "s1", "u1", 50, 3, func() error {
+		return ErrVersionConflict
+	})
+
+	assert.Equal(t, ErrVersionConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRequestRevision_StatusPlaceholders(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	note := "lampirkan sertifikat"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM achievement_references WHERE id = \$1 FOR UPDATE`).
+		WithArgs("a1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StatusSubmitted))
+	// status tujuan dan daftar status asal harus di placeholder yang berbeda
+	mock.ExpectExec(`SET\s+status = \$3,[\s\S]+WHERE id = \$2\s+AND status = ANY\(\$4\)`).
+		WithArgs(note, "a1", model.StatusRevisionRequested, `{"submitted"}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE achievement_submissions`).
+		WithArgs("a1", model.StatusRevisionRequested, "u1", note).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO achievement_status_history`).
+		WithArgs("a1", model.StatusSubmitted, model.StatusRevisionRequested, "u1", note).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := NewAchievementReferenceRepository(db).RequestRevision("a1", "u1", note)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"uas-prestasi/app/model"
+)
+
+type AchievementTypeRepository struct {
+	DB *sql.DB
+}
+
+func NewAchievementTypeRepository(db *sql.DB) *AchievementTypeRepository {
+	return &AchievementTypeRepository{DB: db}
+}
+
+const achievementTypeSelect = `
+	SELECT code, name, COALESCE(description, ''), detail_schema, base_points,
+	       point_rules, is_active, created_at, updated_at
+	FROM achievement_types
+`
+
+func scanAchievementType(row interface{ Scan(...interface{}) error }) (model.AchievementType, error) {
+	var (
+		t          model.AchievementType
+		schemaJSON []byte
+		rulesJSON  []byte
+	)
+
+	err := row.Scan(&t.Code, &t.Name, &t.Description, &schemaJSON, &t.BasePoints,
+		&rulesJSON, &t.IsActive, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return t, err
+	}
+
+	if err := json.Unmarshal(schemaJSON, &t.Schema); err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(rulesJSON, &t.PointRules); err != nil {
+		return t, err
+	}
+	if t.PointRules == nil {
+		t.PointRules = []model.PointRule{}
+	}
+
+	return t, nil
+}
+
+func (r *AchievementTypeRepository) GetAll(activeOnly bool) ([]model.AchievementType, error) {
+	query := achievementTypeSelect
+	if activeOnly {
+		query += " WHERE is_active = true"
+	}
+	query += " ORDER BY name"
+
+	rows, err := r.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []model.AchievementType{}
+	for rows.Next() {
+		t, err := scanAchievementType(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// GetByCode juga mengembalikan tipe nonaktif, pemanggil yang memutuskan
+func (r *AchievementTypeRepository) GetByCode(code string) (*model.AchievementType, error) {
+	t, err := scanAchievementType(r.DB.QueryRow(achievementTypeSelect+" WHERE code = $1", code))
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetNames dipakai laporan untuk menerjemahkan code → nama tampilan
+func (r *AchievementTypeRepository) GetNames() (map[string]string, error) {
+	rows, err := r.DB.Query(`SELECT code, name FROM achievement_types`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := map[string]string{}
+	for rows.Next() {
+		var code, name string
+		if err := rows.Scan(&code, &name); err != nil {
+			return nil, err
+		}
+		names[code] = name
+	}
+
+	return names, rows.Err()
+}
+
+func (r *AchievementTypeRepository) Create(t *model.AchievementType) error {
+	schemaJSON, rulesJSON, err := marshalAchievementType(t)
+	if err != nil {
+		return err
+	}
+
+	return r.DB.QueryRow(`
+		INSERT INTO achievement_types (code, name, description, detail_schema, base_points, point_rules, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`, t.Code, t.Name, t.Description, schemaJSON, t.BasePoints, rulesJSON, t.IsActive).Scan(&t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r *AchievementTypeRepository) Update(t *model.AchievementType) error {
+	schemaJSON, rulesJSON, err := marshalAchievementType(t)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.DB.Exec(`
+		UPDATE achievement_types
+		SET name = $1, description = $2, detail_schema = $3, base_points = $4,
+		    point_rules = $5, is_active = $6, updated_at = NOW()
+		WHERE code = $7
+	`, t.Name, t.Description, schemaJSON, t.BasePoints, rulesJSON, t.IsActive, t.Code)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// tipe tidak pernah dihapus karena code-nya tersimpan di dokumen prestasi lama
+func (r *AchievementTypeRepository) SetActive(code string, active bool) error {
+	res, err := r.DB.Exec(`
+		UPDATE achievement_types SET is_active = $1, updated_at = NOW() WHERE code = $2
+	`, active, code)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func marshalAchievementType(t *model.AchievementType) ([]byte, []byte, error) {
+	if t.Schema.Fields == nil {
+		t.Schema.Fields = []model.AchievementField{}
+	}
+	if t.PointRules == nil {
+		t.PointRules = []model.PointRule{}
+	}
+
+	schemaJSON, err := json.Marshal(t.Schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	rulesJSON, err := json.Marshal(t.PointRules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return schemaJSON, rulesJSON, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"uas-prestasi/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AchievementVersionRepository menyimpan snapshot dokumen achievements.
+// Snapshot hanya ditambah dan tidak pernah diubah. Satu-satunya penghapusan
+// adalah Delete saat submit batal setelah snapshot-nya tersimpan, supaya tidak
+// ada versi pengajuan yang tidak pernah benar-benar diajukan.
+type AchievementVersionRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewAchievementVersionRepository(db *mongo.Database) *AchievementVersionRepository {
+	return &AchievementVersionRepository{
+		Collection: db.Collection("achievement_versions"),
+	}
+}
+
+// EnsureIndexes: satu snapshot per (achievementId, version)
+func (r *AchievementVersionRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "achievementId", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Save idempoten: kalau versi yang sama sudah disalin, snapshot lama dipertahankan
+func (r *AchievementVersionRepository) Save(snapshot model.AchievementSnapshot) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	delete(snapshot.Document, "_id")
+
+	_, err := r.Collection.UpdateOne(
+		ctx,
+		bson.M{"achievementId": snapshot.AchievementID, "version": snapshot.Version},
+		bson.M{"$setOnInsert": snapshot},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// Delete menghapus satu snapshot, dipakai untuk membatalkan snapshot dari
+// pengajuan yang gagal disimpan
+func (r *AchievementVersionRepository) Delete(achievementID string, version int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.Collection.DeleteOne(ctx, bson.M{"achievementId": achievementID, "version": version})
+	return err
+}
+
+// List mengembalikan metadata snapshot (tanpa isi dokumen), urut versi
+func (r *AchievementVersionRepository) List(achievementID string) ([]model.AchievementSnapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.Collection.Find(ctx,
+		bson.M{"achievementId": achievementID},
+		options.Find().
+			SetSort(bson.D{{Key: "version", Value: 1}}).
+			SetProjection(bson.M{"document": 0}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	snapshots := []model.AchievementSnapshot{}
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// Get mengambil satu snapshot lengkap; mongo.ErrNoDocuments kalau tidak ada
+func (r *AchievementVersionRepository) Get(achievementID string, version int) (*model.AchievementSnapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var snapshot model.AchievementSnapshot
+	err := r.Collection.FindOne(ctx, bson.M{"achievementId": achievementID, "version": version}).Decode(&snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -1,7 +1,25 @@
 // app/repository/lecturer_repository.go
 package repository
 
-import "database/sql"
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"uas-prestasi/app/model"
+
+	"github.com/lib/pq"
+)
+
+var ErrDuplicateNIDN = errors.New("nidn sudah terdaftar")
+
+// kode SQLSTATE unique_violation
+const pqUniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
 
 type LecturerRepository struct {
 	DB *sql.DB
@@ -11,59 +29,295 @@ func NewLecturerRepository(db *sql.DB) *LecturerRepository {
 	return &LecturerRepository{DB: db}
 }
 
+// nama diambil dari full_name user, jurusan dari org_units
+const lecturerSelect = `
+	SELECT l.id, l.user_id, u.full_name, u.email, COALESCE(l.nidn, ''),
+	       l.org_unit_id, COALESCE(o.name, ''), l.is_active,
+	       (SELECT COUNT(*) FROM students s WHERE s.advisor_id = l.id)
+	FROM lecturers l
+	JOIN users u ON u.id = l.user_id
+	LEFT JOIN org_units o ON o.id = l.org_unit_id
+`
+
+func scanLecturer(row interface{ Scan(...interface{}) error }) (model.Lecturer, error) {
+	var l model.Lecturer
+	err := row.Scan(
+		&l.ID, &l.UserID, &l.Name, &l.Email, &l.NIDN,
+		&l.OrgUnitID, &l.Department, &l.IsActive, &l.AdviseeCount,
+	)
+	return l, err
+}
 
 // GET /lecturers
-func (r *LecturerRepository) GetAll() ([]map[string]interface{}, error) {
-	rows, err := r.DB.Query(`
-		SELECT l.id, l.user_id, u.username
-		FROM lecturers l
-		JOIN users u ON u.id = l.user_id
-	`)
+func (r *LecturerRepository) GetAll(scope []string) ([]model.Lecturer, error) {
+	query := lecturerSelect
+	args := []interface{}{}
+
+	if scope != nil {
+		query += " WHERE l.org_unit_id = ANY($1)"
+		args = append(args, pq.Array(scope))
+	}
+	query += " ORDER BY u.full_name"
+
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var result []map[string]interface{}
+	result := []model.Lecturer{}
 	for rows.Next() {
-		var id, userID, name string
-		rows.Scan(&id, &userID, &name)
+		l, err := scanLecturer(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, l)
+	}
+
+	return result, rows.Err()
+}
 
-		result = append(result, map[string]interface{}{
-			"id":      id,
-			"user_id": userID,
-			"name":    name,
-		})
+// GET /lecturers/:id
+func (r *LecturerRepository) GetByID(id string) (*model.Lecturer, error) {
+	l, err := scanLecturer(r.DB.QueryRow(lecturerSelect+" WHERE l.id = $1", id))
+	if err != nil {
+		return nil, err
 	}
+	return &l, nil
+}
 
-	return result, nil
+func (r *LecturerRepository) GetByUserID(userID string) (*model.Lecturer, error) {
+	l, err := scanLecturer(r.DB.QueryRow(lecturerSelect+" WHERE l.user_id = $1", userID))
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *LecturerRepository) GetByIDs(ids []string) (map[string]model.Lecturer, error) {
+	rows, err := r.DB.Query(lecturerSelect+" WHERE l.id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[string]model.Lecturer{}
+	for rows.Next() {
+		l, err := scanLecturer(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[l.ID] = l
+	}
+
+	return result, rows.Err()
+}
+
+// dosen aktif yang mengajar di prodi tsb atau di jurusan induknya
+func (r *LecturerRepository) GetActiveForStudyProgram(studyProgramID string) ([]model.Lecturer, error) {
+	rows, err := r.DB.Query(lecturerSelect+`
+		WHERE l.is_active
+		  AND (l.org_unit_id = $1
+		       OR l.org_unit_id = (SELECT parent_id FROM org_units WHERE id = $1))
+		ORDER BY u.full_name
+	`, studyProgramID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []model.Lecturer{}
+	for rows.Next() {
+		l, err := scanLecturer(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, l)
+	}
+
+	return result, rows.Err()
+}
+
+// POST /lecturers
+func (r *LecturerRepository) Create(req model.CreateLecturerRequest) (string, error) {
+	var id string
+	err := r.DB.QueryRow(`
+		INSERT INTO lecturers (user_id, nidn, org_unit_id, is_active)
+		VALUES ($1, $2, $3, true)
+		RETURNING id
+	`, req.UserID, req.NIDN, req.OrgUnitID).Scan(&id)
+	if isUniqueViolation(err) {
+		return "", ErrDuplicateNIDN
+	}
+	return id, err
+}
+
+// PUT /lecturers/:id
+func (r *LecturerRepository) Update(id string, req model.UpdateLecturerRequest) error {
+	res, err := r.DB.Exec(`
+		UPDATE lecturers SET nidn = $1, org_unit_id = $2
+		WHERE id = $3
+	`, req.NIDN, req.OrgUnitID, id)
+	if isUniqueViolation(err) {
+		return ErrDuplicateNIDN
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *LecturerRepository) SetActive(id string, active bool) error {
+	res, err := r.DB.Exec(`UPDATE lecturers SET is_active = $1 WHERE id = $2`, active, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// cek user sudah punya profil dosen / mahasiswa
+func (r *LecturerRepository) UserProfileExists(userID string) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM lecturers WHERE user_id = $1)
+		    OR EXISTS (SELECT 1 FROM students WHERE user_id = $1)
+	`, userID).Scan(&exists)
+	return exists, err
+}
+
+// dosen hanya boleh ditempatkan di jurusan atau program studi yang aktif
+func (r *LecturerRepository) IsTeachingUnit(unitID string) (bool, error) {
+	var ok bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM org_units
+			WHERE id = $1 AND is_active AND type IN ('department', 'study_program')
+		)
+	`, unitID).Scan(&ok)
+	return ok, err
 }
 
 // GET /lecturers/:id/advisees
-func (r *LecturerRepository) GetAdvisees(lecturerID string) ([]map[string]interface{}, error) {
+func (r *LecturerRepository) GetAdvisees(lecturerID string) ([]model.AdviseeResponse, error) {
 	rows, err := r.DB.Query(`
-		SELECT id, user_id, advisor_id
-		FROM students
-		WHERE advisor_id=$1
+		SELECT s.id, s.user_id, s.advisor_id,
+		       COALESCE(u.full_name, ''), COALESCE(s.nim, ''),
+		       COALESCE(sp.name, s.study_program, ''),
+		       COUNT(ar.id) FILTER (WHERE ar.status = 'submitted'),
+		       COUNT(ar.id) FILTER (WHERE ar.status = 'verified')
+		FROM students s
+		LEFT JOIN users u ON u.id = s.user_id
+		LEFT JOIN org_units sp ON sp.id = s.study_program_id
+		LEFT JOIN achievement_references ar ON ar.student_id = s.id
+		WHERE s.advisor_id=$1
+		GROUP BY s.id, u.full_name, sp.name
+		ORDER BY s.nim
 	`, lecturerID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var result []map[string]interface{}
+	result := []model.AdviseeResponse{}
+	for rows.Next() {
+		var a model.AdviseeResponse
+		if err := rows.Scan(
+			&a.ID, &a.UserID, &a.AdvisorID,
+			&a.FullName, &a.NIM, &a.StudyProgram,
+			&a.PendingCount, &a.VerifiedCount,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, a)
+	}
+
+	return result, rows.Err()
+}
+
+// submitted_at paling lama dari prestasi mahasiswa bimbingan yang belum diproses
+func (r *LecturerRepository) GetOldestPendingSubmission(lecturerID string) (*time.Time, error) {
+	var oldest *time.Time
+	err := r.DB.QueryRow(`
+		SELECT MIN(ar.submitted_at)
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		WHERE s.advisor_id = $1
+		  AND ar.status = 'submitted'
+	`, lecturerID).Scan(&oldest)
+	return oldest, err
+}
+
+// verifikasi / penolakan / permintaan revisi terakhir oleh dosen, diambil dari
+// keputusan per pengajuan (decided_by berisi user id dosen)
+func (r *LecturerRepository) GetRecentDecisions(lecturerUserID string, limit int) ([]model.DashboardDecision, error) {
+	rows, err := r.DB.Query(`
+		SELECT ar.id, ar.student_id, COALESCE(u.full_name, ''), sub.decision,
+		       sub.decided_at, sub.note
+		FROM achievement_submissions sub
+		JOIN achievement_references ar ON ar.id = sub.achievement_id
+		JOIN students s ON s.id = ar.student_id
+		LEFT JOIN users u ON u.id = s.user_id
+		WHERE sub.decided_by = $1
+		  AND sub.decision = ANY($2)
+		  AND sub.decided_at IS NOT NULL
+		ORDER BY sub.decided_at DESC
+		LIMIT $3
+	`, lecturerUserID, pq.Array(model.ReviewDecisions()), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []model.DashboardDecision{}
 	for rows.Next() {
-		var id, userID string
-		var advisorID *string
+		var d model.DashboardDecision
+		if err := rows.Scan(&d.AchievementID, &d.StudentID, &d.StudentName, &d.Status, &d.DecidedAt, &d.Note); err != nil {
+			return nil, err
+		}
+		result = append(result, d)
+	}
 
-		rows.Scan(&id, &userID, &advisorID)
+	return result, rows.Err()
+}
+
+// id mahasiswa bimbingan yang belum membuat prestasi sejak tanggal tertentu
+func (r *LecturerRepository) GetAdviseesWithoutAchievementSince(lecturerID string, since time.Time) ([]string, error) {
+	rows, err := r.DB.Query(`
+		SELECT s.id
+		FROM students s
+		WHERE s.advisor_id = $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM achievement_references ar
+			WHERE ar.student_id = s.id
+			  AND ar.status <> 'deleted'
+			  AND ar.created_at >= $2
+		  )
+	`, lecturerID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		result = append(result, map[string]interface{}{
-			"id":         id,
-			"user_id":    userID,
-			"advisor_id": advisorID,
-		})
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 
-	return result, nil
+	return ids, rows.Err()
 }
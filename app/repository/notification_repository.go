@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+
+	"uas-prestasi/app/model"
+)
+
+type NotificationRepository struct {
+	DB *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{DB: db}
+}
+
+// insertNotification dipanggil di dalam transaksi perubahan yang memicu notifikasi
+func insertNotification(tx *sql.Tx, n model.Notification) error {
+	_, err := tx.Exec(`
+		INSERT INTO notifications (user_id, type, title, message, achievement_id)
+		VALUES ($1, $2, $3, $4, $5)
+	`, n.UserID, n.Type, n.Title, n.Message, n.AchievementID)
+	return err
+}
+
+func (r *NotificationRepository) GetByUser(userID string, unreadOnly bool, limit int) ([]model.Notification, error) {
+	query := `
+		SELECT id, user_id, type, title, message, achievement_id, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1
+	`
+	if unreadOnly {
+		query += " AND read_at IS NULL"
+	}
+	query += " ORDER BY created_at DESC LIMIT $2"
+
+	rows, err := r.DB.Query(query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []model.Notification{}
+	for rows.Next() {
+		var n model.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message,
+			&n.AchievementID, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *NotificationRepository) CountUnread(userID string) (int, error) {
+	var total int
+	err := r.DB.QueryRow(`
+		SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL
+	`, userID).Scan(&total)
+	return total, err
+}
+
+// MarkRead hanya bisa menandai notifikasi milik user sendiri
+func (r *NotificationRepository) MarkRead(id, userID string) error {
+	res, err := r.DB.Exec(`
+		UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllRead(userID string) error {
+	_, err := r.DB.Exec(`
+		UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL
+	`, userID)
+	return err
+}
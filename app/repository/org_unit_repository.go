@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"uas-prestasi/app/model"
+
+	"github.com/lib/pq"
+)
+
+var ErrOrgUnitInUse = errors.New("unit masih memiliki sub-unit, mahasiswa, dosen, atau user")
+
+type OrgUnitRepository struct {
+	DB *sql.DB
+}
+
+func NewOrgUnitRepository(db *sql.DB) *OrgUnitRepository {
+	return &OrgUnitRepository{DB: db}
+}
+
+const orgUnitSelect = `
+	SELECT id, parent_id, type, code, name, is_active, created_at, updated_at
+	FROM org_units
+`
+
+func scanOrgUnit(row interface{ Scan(...interface{}) error }) (model.OrgUnit, error) {
+	var u model.OrgUnit
+	err := row.Scan(&u.ID, &u.ParentID, &u.Type, &u.Code, &u.Name, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
+	return u, err
+}
+
+func (r *OrgUnitRepository) GetAll(unitType, parentID string, scope []string) ([]model.OrgUnit, error) {
+	where := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if unitType != "" {
+		where = append(where, fmt.Sprintf("type = $%d", argIndex))
+		args = append(args, unitType)
+		argIndex++
+	}
+	if parentID != "" {
+		where = append(where, fmt.Sprintf("parent_id = $%d", argIndex))
+		args = append(args, parentID)
+		argIndex++
+	}
+	if scope != nil {
+		where = append(where, fmt.Sprintf("id = ANY($%d)", argIndex))
+		args = append(args, pq.Array(scope))
+	}
+
+	query := orgUnitSelect
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY type, name"
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []model.OrgUnit{}
+	for rows.Next() {
+		u, err := scanOrgUnit(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, u)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *OrgUnitRepository) GetByID(id string) (*model.OrgUnit, error) {
+	u, err := scanOrgUnit(r.DB.QueryRow(orgUnitSelect+" WHERE id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *OrgUnitRepository) Create(u *model.OrgUnit) error {
+	return r.DB.QueryRow(`
+		INSERT INTO org_units (parent_id, type, code, name, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, u.ParentID, u.Type, u.Code, u.Name, u.IsActive).Scan(&u.ID, &u.CreatedAt, &u.UpdatedAt)
+}
+
+func (r *OrgUnitRepository) Update(u *model.OrgUnit) error {
+	res, err := r.DB.Exec(`
+		UPDATE org_units
+		SET parent_id = $1, code = $2, name = $3, is_active = $4, updated_at = NOW()
+		WHERE id = $5
+	`, u.ParentID, u.Code, u.Name, u.IsActive, u.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// hapus hanya kalau unit sudah tidak dipakai, selebihnya cukup dinonaktifkan
+func (r *OrgUnitRepository) Delete(id string) error {
+	var inUse bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM org_units WHERE parent_id = $1)
+		    OR EXISTS (SELECT 1 FROM students WHERE study_program_id = $1)
+		    OR EXISTS (SELECT 1 FROM lecturers WHERE org_unit_id = $1)
+		    OR EXISTS (SELECT 1 FROM users WHERE org_unit_id = $1)
+	`, id).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrOrgUnitInUse
+	}
+
+	res, err := r.DB.Exec(`DELETE FROM org_units WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// id unit beserta seluruh turunannya, unit root selalu di index 0
+func (r *OrgUnitRepository) GetDescendantIDs(rootID string) ([]string, error) {
+	rows, err := r.DB.Query(`
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth FROM org_units WHERE id = $1
+			UNION ALL
+			SELECT o.id, t.depth + 1
+			FROM org_units o
+			JOIN tree t ON o.parent_id = t.id
+		)
+		SELECT id FROM tree ORDER BY depth
+	`, rootID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetUserScope mengembalikan unit organisasi user, flag super admin, dan apakah
+// role-nya punya salah satu scopedPermissions (akses tipe admin yang dibatasi unit).
+func (r *OrgUnitRepository) GetUserScope(userID string, scopedPermissions []string) (*string, bool, bool, error) {
+	var (
+		unitID     *string
+		superAdmin bool
+		scoped     bool
+	)
+	err := r.DB.QueryRow(`
+		SELECT u.org_unit_id, u.is_super_admin,
+		       EXISTS (
+		           SELECT 1 FROM role_permissions rp
+		           JOIN permissions p ON p.id = rp.permission_id
+		           WHERE rp.role_id = u.role_id AND p.name = ANY($2)
+		       )
+		FROM users u
+		WHERE u.id = $1
+	`, userID, pq.Array(scopedPermissions)).Scan(&unitID, &superAdmin, &scoped)
+	if err != nil {
+		return nil, false, false, err
+	}
+	return unitID, superAdmin, scoped, nil
+}
+
+func (r *OrgUnitRepository) assign(query string, id string, unitID *string) error {
+	res, err := r.DB.Exec(query, unitID, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *OrgUnitRepository) AssignStudent(studentID string, unitID *string) error {
+	return r.assign(`UPDATE students SET study_program_id = $1 WHERE id = $2`, studentID, unitID)
+}
+
+func (r *OrgUnitRepository) AssignLecturer(lecturerID string, unitID *string) error {
+	return r.assign(`UPDATE lecturers SET org_unit_id = $1 WHERE id = $2`, lecturerID, unitID)
+}
+
+func (r *OrgUnitRepository) AssignUser(userID string, unitID *string) error {
+	return r.assign(`UPDATE users SET org_unit_id = $1, updated_at = NOW() WHERE id = $2`, userID, unitID)
+}
+
+// unit tempat mahasiswa / dosen terdaftar, dipakai untuk cek scope
+func (r *OrgUnitRepository) GetStudentUnit(studentID string) (*string, error) {
+	var unitID *string
+	err := r.DB.QueryRow(`SELECT study_program_id FROM students WHERE id = $1`, studentID).Scan(&unitID)
+	return unitID, err
+}
+
+func (r *OrgUnitRepository) GetLecturerUnit(lecturerID string) (*string, error) {
+	var unitID *string
+	err := r.DB.QueryRow(`SELECT org_unit_id FROM lecturers WHERE id = $1`, lecturerID).Scan(&unitID)
+	return unitID, err
+}
+
+// user masuk scope kalau dia mahasiswa/dosen/admin di salah satu unit scope
+func (r *OrgUnitRepository) IsUserInScope(userID string, scope []string) (bool, error) {
+	var ok bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM users u
+			LEFT JOIN students s ON s.user_id = u.id
+			LEFT JOIN lecturers l ON l.user_id = u.id
+			WHERE u.id = $1
+			  AND (s.study_program_id = ANY($2)
+			       OR l.org_unit_id = ANY($2)
+			       OR u.org_unit_id = ANY($2))
+		)
+	`, userID, pq.Array(scope)).Scan(&ok)
+	return ok, err
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"uas-prestasi/app/model"
+)
+
+type OutboxRepository struct {
+	DB *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{DB: db}
+}
+
+// insertOutboxEvent dipanggil di dalam transaksi yang mengubah achievement_references
+func insertOutboxEvent(tx *sql.Tx, aggregateID, eventType string, payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	var id string
+	err = tx.QueryRow(`
+		INSERT INTO outbox_events (aggregate_id, event_type, payload)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, aggregateID, eventType, data).Scan(&id)
+	return id, err
+}
+
+const outboxReturning = `
+	RETURNING id, aggregate_id, event_type, payload, attempts, last_error,
+	          available_at, processed_at, created_at
+`
+
+func scanOutboxEvents(rows *sql.Rows) ([]model.OutboxEvent, error) {
+	defer rows.Close()
+
+	events := []model.OutboxEvent{}
+	for rows.Next() {
+		var e model.OutboxEvent
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.AggregateID, &e.EventType, &payload, &e.Attempts, &e.LastError,
+			&e.AvailableAt, &e.ProcessedAt, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.Payload = payload
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// ClaimPending mengambil event yang siap diproses dan menyewanya selama lease,
+// sehingga dispatcher lain (atau instance lain) tidak memproses event yang sama.
+// Kalau proses mati di tengah jalan, event tersedia lagi setelah lease habis.
+func (r *OutboxRepository) ClaimPending(limit, maxAttempts int, lease time.Duration) ([]model.OutboxEvent, error) {
+	rows, err := r.DB.Query(`
+		UPDATE outbox_events
+		SET available_at = NOW() + $3 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE processed_at IS NULL
+			  AND available_at <= NOW()
+			  AND attempts < $2
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+	`+outboxReturning, limit, maxAttempts, int(lease.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanOutboxEvents(rows)
+}
+
+// ClaimByID dipakai untuk langsung memproses event setelah commit.
+// sql.ErrNoRows kalau event sudah diproses atau sedang dipegang dispatcher lain.
+func (r *OutboxRepository) ClaimByID(id string, lease time.Duration) (*model.OutboxEvent, error) {
+	rows, err := r.DB.Query(`
+		UPDATE outbox_events
+		SET available_at = NOW() + $2 * INTERVAL '1 second'
+		WHERE id = $1
+		  AND processed_at IS NULL
+		  AND available_at <= NOW()
+	`+outboxReturning, id, int(lease.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := scanOutboxEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &events[0], nil
+}
+
+func (r *OutboxRepository) MarkProcessed(id string) error {
+	_, err := r.DB.Exec(`
+		UPDATE outbox_events SET processed_at = NOW(), last_error = NULL WHERE id = $1
+	`, id)
+	return err
+}
+
+// MarkFailed menambah attempts dan menjadwalkan percobaan berikutnya
+func (r *OutboxRepository) MarkFailed(id, lastError string, retryAfter time.Duration) error {
+	_, err := r.DB.Exec(`
+		UPDATE outbox_events
+		SET attempts = attempts + 1,
+		    last_error = $2,
+		    available_at = NOW() + $3 * INTERVAL '1 second'
+		WHERE id = $1
+	`, id, lastError, int(retryAfter.Seconds()))
+	return err
+}
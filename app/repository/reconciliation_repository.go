@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"uas-prestasi/app/model"
+
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrQuarantineNotAllowed: status reference sudah punya keputusan dosen,
+// tidak boleh dihapus otomatis oleh rekonsiliasi
+var ErrQuarantineNotAllowed = errors.New("status prestasi tidak bisa dikarantina otomatis, perlu penanganan manual")
+
+// ReconciliationRepository membaca kedua store sekaligus, seperti ReportRepository
+type ReconciliationRepository struct {
+	DB    *sql.DB
+	Mongo *mongo.Database
+}
+
+func NewReconciliationRepository(db *sql.DB, mongo *mongo.Database) *ReconciliationRepository {
+	return &ReconciliationRepository{DB: db, Mongo: mongo}
+}
+
+// ListReferences mengambil semua reference, termasuk yang berstatus deleted,
+// karena dokumen Mongo-nya tetap disimpan. Reference yang dibuat dalam rentang
+// settle ikut ditandai pending karena bisa saja lebih baru dari snapshot Mongo.
+func (r *ReconciliationRepository) ListReferences(maxOutboxAttempts int, settle time.Duration) ([]model.ReconcileReference, error) {
+	rows, err := r.DB.Query(`
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.awarded_points,
+		       ar.created_at >= NOW() - $2 * INTERVAL '1 second'
+		       OR EXISTS (
+		           SELECT 1 FROM outbox_events o
+		           WHERE o.aggregate_id = ar.id
+		             AND o.processed_at IS NULL
+		             AND o.attempts < $1
+		       )
+		FROM achievement_references ar
+		ORDER BY ar.created_at
+	`, maxOutboxAttempts, int(settle.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []model.ReconcileReference{}
+	for rows.Next() {
+		var ref model.ReconcileReference
+		var awarded sql.NullInt64
+		if err := rows.Scan(&ref.ID, &ref.StudentID, &ref.MongoID, &ref.Status, &awarded, &ref.PendingOutbox); err != nil {
+			return nil, err
+		}
+		if awarded.Valid {
+			points := int(awarded.Int64)
+			ref.AwardedPoints = &points
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+// ListDocuments mengambil proyeksi semua dokumen achievements, di-key dengan hex _id
+func (r *ReconciliationRepository) ListDocuments() (map[string]model.ReconcileDocument, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	opts := options.Find().SetProjection(bson.M{"studentId": 1, "points": 1, "status": 1})
+	cursor, err := r.Mongo.Collection("achievements").Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := map[string]model.ReconcileDocument{}
+	for cursor.Next(ctx) {
+		var raw struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			StudentID string             `bson:"studentId"`
+			Points    int                `bson:"points"`
+			Status    *string            `bson:"status"`
+		}
+		if err := cursor.Decode(&raw); err != nil {
+			return nil, err
+		}
+		docs[raw.ID.Hex()] = model.ReconcileDocument{
+			MongoID:   raw.ID.Hex(),
+			StudentID: raw.StudentID,
+			Points:    raw.Points,
+			Status:    raw.Status,
+		}
+	}
+
+	return docs, cursor.Err()
+}
+
+func (r *ReconciliationRepository) HasUnprocessedDraftEvent(referenceID string) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM outbox_events
+			WHERE aggregate_id = $1 AND event_type = $2 AND processed_at IS NULL
+		)
+	`, referenceID, model.OutboxAchievementDraftCreated).Scan(&exists)
+	return exists, err
+}
+
+// RequeueDraftEvent menghidupkan lagi event draft_created yang sudah melewati
+// batas percobaan. false kalau reference tidak punya event seperti itu.
+func (r *ReconciliationRepository) RequeueDraftEvent(referenceID string) (bool, error) {
+	res, err := r.DB.Exec(`
+		UPDATE outbox_events
+		SET attempts = 0, last_error = NULL, available_at = NOW()
+		WHERE aggregate_id = $1
+		  AND event_type = $2
+		  AND processed_at IS NULL
+	`, referenceID, model.OutboxAchievementDraftCreated)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+// QuarantineReference menandai reference tanpa dokumen sebagai deleted lewat
+// transisi quarantine (hanya draft/submitted). Pengajuan yang masih terbuka
+// ditutup sebagai withdrawn. Perubahannya tercatat di achievement_status_history
+// dengan catatan alasan, jadi masih bisa ditelusuri dan dipulihkan manual.
+func (r *ReconciliationRepository) QuarantineReference(referenceID, actorUserID, note string) error {
+	quarantine := model.AchievementTransitions[model.ActionQuarantine]
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	from, err := lockStatus(tx, referenceID)
+	if err != nil {
+		return err
+	}
+	if !model.CanTransition(model.ActionQuarantine, from) {
+		return ErrQuarantineNotAllowed
+	}
+
+	_, err = tx.Exec(`
+		UPDATE achievement_references SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = ANY($3)
+	`, referenceID, quarantine.To, pq.Array(quarantine.From))
+	if err != nil {
+		return err
+	}
+
+	if err := closeSubmission(tx, referenceID, "withdrawn", actorUserID, &note); err != nil {
+		return err
+	}
+
+	if err := recordStatusChange(tx, referenceID, &from, quarantine.To, actorUserID, &note); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// QuarantineDocument memindahkan dokumen tanpa reference ke koleksi
+// achievements_quarantine, bukan menghapusnya
+func (r *ReconciliationRepository) QuarantineDocument(mongoID, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(mongoID)
+	if err != nil {
+		return err
+	}
+
+	var doc bson.M
+	if err := r.Mongo.Collection("achievements").FindOne(ctx, bson.M{"_id": objID}).Decode(&doc); err != nil {
+		return err
+	}
+
+	doc["quarantinedAt"] = time.Now()
+	doc["quarantineReason"] = reason
+
+	// upsert supaya aman dijalankan ulang kalau DeleteOne sebelumnya gagal
+	_, err = r.Mongo.Collection("achievements_quarantine").ReplaceOne(ctx,
+		bson.M{"_id": objID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Mongo.Collection("achievements").DeleteOne(ctx, bson.M{"_id": objID})
+	return err
+}
@@ -50,10 +50,39 @@ func (r *ReportRepository) GetStudentNamesFromStudents(ids []string) (map[string
 	return result, nil
 }
 
+// program studi tiap mahasiswa, untuk statistik per prodi
+func (r *ReportRepository) GetStudyProgramsByStudentIDs(ids []string) (map[string]string, error) {
+	if len(ids) == 0 {
+		return map[string]string{}, nil
+	}
+
+	rows, err := r.DB.Query(`
+		SELECT id, COALESCE(study_program, '')
+		FROM students
+		WHERE id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]string)
+	for rows.Next() {
+		var studentID, program string
+		if err := rows.Scan(&studentID, &program); err != nil {
+			return nil, err
+		}
+		result[studentID] = program
+	}
+
+	return result, nil
+}
+
 // =============================
 // Ambil achievement references berdasarkan role
 // =============================
-func (r *ReportRepository) GetAchievementReferencesByUser(userID, roleID string) ([]string, error) {
+// scope = id unit organisasi untuk admin unit, nil = semua
+func (r *ReportRepository) GetAchievementReferencesByUser(userID, roleID string, scope []string) ([]string, error) {
 
 	var roleName string
 	err := r.DB.QueryRow(`SELECT name FROM roles WHERE id = $1`, roleID).Scan(&roleName)
@@ -75,7 +104,11 @@ func (r *ReportRepository) GetAchievementReferencesByUser(userID, roleID string)
 
 	switch roleName {
 	case "admin":
-		rows, err = r.DB.Query(baseQuery)
+		if scope != nil {
+			rows, err = r.DB.Query(baseQuery+" AND s.study_program_id = ANY($1)", pq.Array(scope))
+		} else {
+			rows, err = r.DB.Query(baseQuery)
+		}
 
 	case "mahasiswa":
 		rows, err = r.DB.Query(baseQuery+" AND s.user_id = $1", userID)
@@ -104,6 +137,13 @@ func (r *ReportRepository) GetAchievementReferencesByUser(userID, roleID string)
 	return ids, nil
 }
 
+// program studi mahasiswa, untuk cek scope report per mahasiswa
+func (r *ReportRepository) GetStudentUnit(studentID string) (*string, error) {
+	var unitID *string
+	err := r.DB.QueryRow(`SELECT study_program_id FROM students WHERE id = $1`, studentID).Scan(&unitID)
+	return unitID, err
+}
+
 // =============================
 // Generic aggregate untuk Mongo
 // =============================
@@ -2,9 +2,22 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"uas-prestasi/app/model"
+
+	"github.com/lib/pq"
 )
 
+// prestasi submitted diverifikasi dosen wali mahasiswa, jadi dosen wali tidak
+// boleh dilepas selama masih ada yang menunggu verifikasi
+var ErrPendingSubmissions = errors.New("mahasiswa masih memiliki prestasi yang menunggu verifikasi")
+
+var ErrDuplicateNIM = errors.New("nim sudah terdaftar")
+
 type StudentRepository struct {
 	DB *sql.DB
 }
@@ -14,49 +27,125 @@ func NewStudentRepository(db *sql.DB) *StudentRepository {
 }
 
 
+// nama prodi & fakultas diambil dari org_units kalau mahasiswa sudah terhubung,
+// kalau belum pakai kolom teks lama
+const studentSelect = `
+	SELECT s.id, s.user_id, COALESCE(u.full_name, ''), COALESCE(s.nim, ''),
+	       s.study_program_id,
+	       COALESCE(sp.name, s.study_program, ''), COALESCE(f.name, s.faculty, ''),
+	       COALESCE(s.entry_year, 0), COALESCE(s.semester, 0),
+	       COALESCE(s.academic_status, 'active'), s.advisor_id
+	FROM students s
+	LEFT JOIN users u ON u.id = s.user_id
+	LEFT JOIN org_units sp ON sp.id = s.study_program_id
+	LEFT JOIN org_units d ON d.id = sp.parent_id
+	LEFT JOIN org_units f ON f.id = d.parent_id
+`
+
+func scanStudent(row interface{ Scan(...interface{}) error }) (model.Student, error) {
+	var st model.Student
+	err := row.Scan(
+		&st.ID, &st.UserID, &st.FullName, &st.NIM,
+		&st.StudyProgramID, &st.StudyProgram, &st.Faculty,
+		&st.EntryYear, &st.Semester,
+		&st.AcademicStatus, &st.AdvisorID,
+	)
+	return st, err
+}
+
 // GET /students
-func (r *StudentRepository) GetAll() ([]map[string]interface{}, error) {
-	rows, err := r.DB.Query(`SELECT id, user_id, advisor_id FROM students`)
+func (r *StudentRepository) GetAll(filter model.StudentFilter) ([]model.Student, error) {
+	where := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	addFilter := func(cond string, value interface{}) {
+		where = append(where, fmt.Sprintf(cond, argIndex))
+		args = append(args, value)
+		argIndex++
+	}
+
+	if filter.NIM != "" {
+		addFilter("s.nim = $%d", filter.NIM)
+	}
+	if filter.StudyProgramID != "" {
+		addFilter("s.study_program_id = $%d", filter.StudyProgramID)
+	}
+	if filter.StudyProgram != "" {
+		addFilter("COALESCE(sp.name, s.study_program) ILIKE $%d", filter.StudyProgram)
+	}
+	if filter.Faculty != "" {
+		addFilter("COALESCE(f.name, s.faculty) ILIKE $%d", filter.Faculty)
+	}
+	if filter.EntryYear > 0 {
+		addFilter("s.entry_year = $%d", filter.EntryYear)
+	}
+	if filter.Semester > 0 {
+		addFilter("s.semester = $%d", filter.Semester)
+	}
+	if filter.AcademicStatus != "" {
+		addFilter("s.academic_status = $%d", filter.AcademicStatus)
+	}
+	if filter.Scope != nil {
+		addFilter("s.study_program_id = ANY($%d)", pq.Array(filter.Scope))
+	}
+
+	query := studentSelect
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY s.nim"
+
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var result []map[string]interface{}
+	result := []model.Student{}
 	for rows.Next() {
-		var id, userID string
-		var advisorID *string
-
-		rows.Scan(&id, &userID, &advisorID)
-
-		result = append(result, map[string]interface{}{
-			"id":         id,
-			"user_id":    userID,
-			"advisor_id": advisorID,
-		})
+		st, err := scanStudent(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, st)
 	}
 
-	return result, nil
+	return result, rows.Err()
 }
 
 // GET /students/:id
-func (r *StudentRepository) GetByID(id string) (map[string]interface{}, error) {
-	row := r.DB.QueryRow(`
-		SELECT id, user_id, advisor_id FROM students WHERE id=$1
-	`, id)
-
-	var sid, uid string
-	var aid *string
-	err := row.Scan(&sid, &uid, &aid)
+func (r *StudentRepository) GetByID(id string) (*model.Student, error) {
+	st, err := scanStudent(r.DB.QueryRow(studentSelect+" WHERE s.id=$1", id))
 	if err != nil {
 		return nil, err
 	}
 
-	return map[string]interface{}{
-		"id":         sid,
-		"user_id":    uid,
-		"advisor_id": aid,
-	}, nil
+	return &st, nil
+}
+
+// PUT /students/:id
+func (r *StudentRepository) UpdateProfile(id string, req model.UpdateStudentRequest) error {
+	res, err := r.DB.Exec(`
+		UPDATE students SET
+			nim=$1, study_program=$2, faculty=$3,
+			entry_year=$4, semester=$5, academic_status=$6
+		WHERE id=$7
+	`, req.NIM, req.StudyProgram, req.Faculty,
+		req.EntryYear, req.Semester, req.AcademicStatus, id)
+	if isUniqueViolation(err) {
+		return ErrDuplicateNIM
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
 
 func (r *StudentRepository) GetAchievementsStudents(studentID string) ([]map[string]interface{}, error) {
@@ -87,10 +176,168 @@ func (r *StudentRepository) GetAchievementsStudents(studentID string) ([]map[str
 	return result, nil
 }
 
-func (r *StudentRepository) UpdateAdvisor(studentID, lecturerID string) error {
-	_, err := r.DB.Exec(`
+// UpdateAdvisor mengganti dosen wali dan mencatat periode bimbingan di
+// advisor_history dalam satu transaksi. lecturerID nil = melepas dosen wali,
+// ditolak dengan ErrPendingSubmissions kalau masih ada prestasi submitted.
+// Mengembalikan dosen wali sebelumnya dan jumlah prestasi submitted yang
+// ikut pindah ke dosen wali baru.
+func (r *StudentRepository) UpdateAdvisor(studentID string, lecturerID *string, actorID string) (*string, int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	previous, pending, err := updateAdvisorTx(tx, studentID, lecturerID, actorID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+
+	return previous, pending, nil
+}
+
+// BulkUpdateAdvisor menjalankan banyak assignment sekaligus, semua atau tidak sama sekali
+func (r *StudentRepository) BulkUpdateAdvisor(assignments []model.AdvisorAssignment, actorID string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, a := range assignments {
+		if _, _, err := updateAdvisorTx(tx, a.StudentID, a.LecturerID, actorID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func updateAdvisorTx(tx *sql.Tx, studentID string, lecturerID *string, actorID string) (*string, int, error) {
+	var previous *string
+	err := tx.QueryRow(`
+		SELECT advisor_id FROM students WHERE id=$1 FOR UPDATE
+	`, studentID).Scan(&previous)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// tidak ada perubahan
+	if (previous == nil && lecturerID == nil) ||
+		(previous != nil && lecturerID != nil && *previous == *lecturerID) {
+		return previous, 0, nil
+	}
+
+	var pending int
+	if err := tx.QueryRow(`
+		SELECT COUNT(*) FROM achievement_references
+		WHERE student_id = $1 AND status = 'submitted'
+	`, studentID).Scan(&pending); err != nil {
+		return nil, 0, err
+	}
+	if lecturerID == nil && pending > 0 {
+		return nil, 0, ErrPendingSubmissions
+	}
+
+	if _, err := tx.Exec(`
 		UPDATE students SET advisor_id=$1 WHERE id=$2
-	`, lecturerID, studentID)
+	`, lecturerID, studentID); err != nil {
+		return nil, 0, err
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE advisor_history SET ended_at = NOW()
+		WHERE student_id = $1 AND ended_at IS NULL
+	`, studentID); err != nil {
+		return nil, 0, err
+	}
+
+	if lecturerID != nil {
+		if _, err := tx.Exec(`
+			INSERT INTO advisor_history (student_id, lecturer_id, assigned_by)
+			VALUES ($1, $2, $3)
+		`, studentID, *lecturerID, actorID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return previous, pending, nil
+}
+
+func (r *StudentRepository) GetByIDs(ids []string) (map[string]model.Student, error) {
+	rows, err := r.DB.Query(studentSelect+" WHERE s.id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[string]model.Student{}
+	for rows.Next() {
+		st, err := scanStudent(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[st.ID] = st
+	}
+
+	return result, rows.Err()
+}
+
+// mahasiswa aktif di satu prodi yang belum punya dosen wali
+func (r *StudentRepository) GetUnassigned(studyProgramID string) ([]model.Student, error) {
+	rows, err := r.DB.Query(studentSelect+`
+		WHERE s.study_program_id = $1
+		  AND s.advisor_id IS NULL
+		  AND s.academic_status = 'active'
+		ORDER BY s.nim
+	`, studyProgramID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []model.Student{}
+	for rows.Next() {
+		st, err := scanStudent(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, st)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *StudentRepository) GetAdvisorHistory(studentID string) ([]model.AdvisorHistory, error) {
+	rows, err := r.DB.Query(`
+		SELECT h.id, h.student_id, h.lecturer_id, COALESCE(u.full_name, ''),
+		       h.assigned_at, h.ended_at, h.assigned_by
+		FROM advisor_history h
+		LEFT JOIN lecturers l ON l.id = h.lecturer_id
+		LEFT JOIN users u ON u.id = l.user_id
+		WHERE h.student_id = $1
+		ORDER BY h.assigned_at DESC
+	`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []model.AdvisorHistory{}
+	for rows.Next() {
+		var h model.AdvisorHistory
+		if err := rows.Scan(
+			&h.ID, &h.StudentID, &h.LecturerID, &h.LecturerName,
+			&h.AssignedAt, &h.EndedAt, &h.AssignedBy,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, h)
+	}
 
-	return err
+	return result, rows.Err()
 }
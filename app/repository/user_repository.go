@@ -3,6 +3,8 @@ package repository
 import (
 	"database/sql"
 	"uas-prestasi/app/model"
+
+	"github.com/lib/pq"
 )
 
 type UserRepository struct {
@@ -13,13 +15,29 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{DB: db}
 }
 
-func (r *UserRepository) GetAll() ([]model.User, error) {
-	rows, err := r.DB.Query(`
+// user masuk scope kalau dia mahasiswa/dosen/admin di salah satu unit scope
+const userScopeCondition = `
+	(u.org_unit_id = ANY($1)
+	 OR EXISTS (SELECT 1 FROM students s WHERE s.user_id = u.id AND s.study_program_id = ANY($1))
+	 OR EXISTS (SELECT 1 FROM lecturers l WHERE l.user_id = u.id AND l.org_unit_id = ANY($1)))
+`
+
+// scope = id unit organisasi yang boleh dikelola, nil = semua
+func (r *UserRepository) GetAll(scope []string) ([]model.User, error) {
+	query := `
 		SELECT u.id, u.username, u.email, u.full_name, u.role_id, r.name AS role_name, 
-		       u.is_active
+		       u.is_active, u.org_unit_id
 		FROM users u
 		LEFT JOIN roles r ON r.id = u.role_id
-	`)
+	`
+	args := []interface{}{}
+
+	if scope != nil {
+		query += " WHERE " + userScopeCondition
+		args = append(args, pq.Array(scope))
+	}
+
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +48,7 @@ func (r *UserRepository) GetAll() ([]model.User, error) {
 		var u model.User
 		if err := rows.Scan(
 			&u.ID, &u.Username, &u.Email, &u.FullName,
-			&u.RoleID, &u.RoleName, &u.IsActive,
+			&u.RoleID, &u.RoleName, &u.IsActive, &u.OrgUnitID,
 		); err != nil {
 			return nil, err
 		}
@@ -44,13 +62,13 @@ func (r *UserRepository) GetByID(id string) (*model.User, error) {
 
 	err := r.DB.QueryRow(`
 		SELECT u.id, u.username, u.email, u.full_name, 
-		       u.role_id, r.name AS role_name, u.is_active
+		       u.role_id, r.name AS role_name, u.is_active, u.org_unit_id
 		FROM users u
 		LEFT JOIN roles r ON r.id = u.role_id
 		WHERE u.id = $1
 	`, id).Scan(
 		&u.ID, &u.Username, &u.Email, &u.FullName,
-		&u.RoleID, &u.RoleName, &u.IsActive,
+		&u.RoleID, &u.RoleName, &u.IsActive, &u.OrgUnitID,
 	)
 
 	if err != nil {
@@ -62,22 +80,32 @@ func (r *UserRepository) GetByID(id string) (*model.User, error) {
 
 func (r *UserRepository) Create(u *model.User) error {
 	err := r.DB.QueryRow(`
-		INSERT INTO users (id, username, email, password_hash, full_name, role_id, is_active)
-		VALUES (uuid_generate_v4(), $1, $2, crypt($3, gen_salt('bf')), $4, $5, true)
+		INSERT INTO users (id, username, email, password_hash, full_name, role_id, is_active, org_unit_id)
+		VALUES (uuid_generate_v4(), $1, $2, crypt($3, gen_salt('bf')), $4, $5, true, $6)
 		RETURNING id
-	`, u.Username, u.Email, u.Password, u.FullName, u.RoleID).Scan(&u.ID)
+	`, u.Username, u.Email, u.Password, u.FullName, u.RoleID, u.OrgUnitID).Scan(&u.ID)
 
 	return err
 }
 
 
+func (r *UserRepository) IsInScope(id string, scope []string) (bool, error) {
+	var ok bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM users u WHERE u.id = $2 AND `+userScopeCondition+`)
+	`, pq.Array(scope), id).Scan(&ok)
+	return ok, err
+}
+
+// u.OrgUnitID nil berarti unit tidak diubah
 func (r *UserRepository) Update(id string, u *model.User) error {
 	_, err := r.DB.Exec(`
 		UPDATE users SET 
-			username=$1, email=$2, full_name=$3, role_id=$4, is_active=$5, updated_at=NOW()
+			username=$1, email=$2, full_name=$3, role_id=$4, is_active=$5,
+			org_unit_id=COALESCE($7, org_unit_id), updated_at=NOW()
 		WHERE id=$6
 	`,
-		u.Username, u.Email, u.FullName, u.RoleID, u.IsActive, id,
+		u.Username, u.Email, u.FullName, u.RoleID, u.IsActive, id, u.OrgUnitID,
 	)
 	return err
 }
@@ -89,11 +117,20 @@ func (r *UserRepository) Delete(id string) error {
 }
 
 
-func (r *UserRepository) UpdateRole(id, roleID string) error {
+// orgUnitID nil berarti unit tidak diubah
+func (r *UserRepository) UpdateRole(id, roleID string, orgUnitID *string) error {
 	_, err := r.DB.Exec(`
-		UPDATE users SET role_id=$1, updated_at=NOW()
+		UPDATE users SET role_id=$1, org_unit_id=COALESCE($3, org_unit_id), updated_at=NOW()
 		WHERE id=$2
-	`, roleID, id)
+	`, roleID, id, orgUnitID)
 	return err
 }
 
+func (r *UserRepository) IsAdminRole(roleID string) (bool, error) {
+	var ok bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM roles WHERE id::text = $1 AND name = 'Admin')
+	`, roleID).Scan(&ok)
+	return ok, err
+}
+
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"uas-prestasi/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const maxCommentLength = 4000
+
+// komentar internal hanya untuk admin, tidak terlihat mahasiswa maupun dosen wali
+func (s *AchievementService) canSeeInternalComments(roleID string) bool {
+	ok, _ := s.PermissionService.HasPermission(roleID, "achievement:comment:internal")
+	return ok
+}
+
+// Comments godoc
+// @Summary Achievement comments
+// @Description Thread komentar antara mahasiswa dan dosen wali. Hanya untuk mahasiswa pemilik,
+// dosen walinya, dan admin yang unitnya mencakup mahasiswa tersebut.
+// Komentar internal hanya terlihat oleh admin. Membuka thread menandai komentar yang dikirim sebagai terbaca.
+// @Tags Achievement
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} model.AchievementCommentsResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/{id}/comments [get]
+func (s *AchievementService) Comments(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("user_id").(string)
+	roleID := c.Locals("role_id").(string)
+
+	ref, err := s.RefRepo.GetByID(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"message": "Achievement tidak ditemukan"})
+	}
+	if ok, err := s.canViewAchievement(c, ref); !ok {
+		return viewAccessError(c, err)
+	}
+
+	includeInternal := s.canSeeInternalComments(roleID)
+
+	unread, err := s.CommentRepo.UnreadCounts(userID, []string{id}, includeInternal)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "Gagal mengambil komentar"})
+	}
+
+	comments, err := s.CommentRepo.List(id, includeInternal)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "Gagal mengambil komentar"})
+	}
+
+	if len(comments) > 0 {
+		if err := s.CommentRepo.MarkRead(id, userID, latestCommentAt(comments)); err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "Gagal menandai komentar terbaca"})
+		}
+	}
+
+	var resp model.AchievementCommentsResponse
+	resp.Status = "success"
+	resp.Data = commentThreads(comments)
+	resp.Meta.Total = len(comments)
+	resp.Meta.Unread = unread[id]
+
+	return c.JSON(resp)
+}
+
+// AddComment godoc
+// @Summary Add achievement comment
+// @Description Menambah komentar atau balasan (parent_id). attachment_ref harus berupa file_url
+// salah satu attachment prestasi. internal=true hanya untuk admin; balasan komentar internal
+// selalu internal.
+// @Tags Achievement
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param request body model.CreateCommentRequest true "Komentar"
+// @Success 201 {object} model.AchievementCommentResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/{id}/comments [post]
+func (s *AchievementService) AddComment(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("user_id").(string)
+	roleID := c.Locals("role_id").(string)
+
+	var req model.CreateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Payload invalid"})
+	}
+
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" {
+		return c.Status(400).JSON(fiber.Map{"message": "body wajib diisi"})
+	}
+	if len([]rune(req.Body)) > maxCommentLength {
+		return c.Status(400).JSON(fiber.Map{"message": "body terlalu panjang"})
+	}
+
+	ref, err := s.RefRepo.GetByID(id)
+	if err != nil || ref.Status == model.StatusDeleted {
+		return c.Status(404).JSON(fiber.Map{"message": "Achievement tidak ditemukan"})
+	}
+	if ok, err := s.canViewAchievement(c, ref); !ok {
+		return viewAccessError(c, err)
+	}
+
+	canInternal := s.canSeeInternalComments(roleID)
+	if req.Internal && !canInternal {
+		return c.Status(403).JSON(fiber.Map{"message": "Hanya admin yang dapat membuat komentar internal"})
+	}
+
+	comment := model.AchievementComment{
+		AchievementID: id,
+		AuthorID:      userID,
+		Body:          req.Body,
+		Internal:      req.Internal,
+	}
+
+	if req.ParentID != nil && *req.ParentID != "" {
+		if !isUUID(*req.ParentID) {
+			return c.Status(404).JSON(fiber.Map{"message": "Komentar yang dibalas tidak ditemukan"})
+		}
+		rootID, parentInternal, err := s.CommentRepo.GetThreadRoot(id, *req.ParentID)
+		if err == sql.ErrNoRows || (err == nil && parentInternal && !canInternal) {
+			return c.Status(404).JSON(fiber.Map{"message": "Komentar yang dibalas tidak ditemukan"})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "Gagal membaca komentar"})
+		}
+		comment.ParentID = &rootID
+		// balasan di thread internal tidak boleh bocor ke mahasiswa
+		comment.Internal = comment.Internal || parentInternal
+	}
+
+	if req.AttachmentRef != nil && *req.AttachmentRef != "" {
+		doc, err := s.MongoRepo.FindByID(ref.MongoAchievementID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "Gagal membaca data prestasi"})
+		}
+		if !hasAttachment(doc, *req.AttachmentRef) {
+			return c.Status(400).JSON(fiber.Map{"message": "attachment_ref bukan attachment prestasi ini"})
+		}
+		comment.AttachmentRef = req.AttachmentRef
+	}
+
+	if err := s.CommentRepo.Create(&comment); err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "Gagal menyimpan komentar"})
+	}
+
+	return c.Status(201).JSON(model.AchievementCommentResponse{
+		Status: "success",
+		Data:   comment,
+	})
+}
+
+// commentThreads menyusun komentar kronologis menjadi thread satu tingkat.
+// Balasan yang induknya tidak terlihat (internal) ikut disembunyikan.
+func commentThreads(comments []model.AchievementComment) []model.AchievementComment {
+	threads := []model.AchievementComment{}
+	index := map[string]int{}
+
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			index[comment.ID] = len(threads)
+			threads = append(threads, comment)
+		}
+	}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			continue
+		}
+		if i, ok := index[*comment.ParentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, comment)
+		}
+	}
+
+	return threads
+}
+
+// latestCommentAt: posisi baca = komentar terbaru yang dikirim ke user
+func latestCommentAt(comments []model.AchievementComment) time.Time {
+	var latest time.Time
+	for _, comment := range comments {
+		if comment.CreatedAt.After(latest) {
+			latest = comment.CreatedAt
+		}
+	}
+	return latest
+}
+
+func hasAttachment(doc bson.M, fileURL string) bool {
+	attachments, _ := doc["attachments"].(bson.A)
+	for _, a := range attachments {
+		if m, ok := a.(bson.M); ok && m["fileUrl"] == fileURL {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCommentThreads(t *testing.T) {
+	c1, c3 := "c1", "c3"
+	comments := []model.AchievementComment{
+		{ID: "c1", Body: "Sertifikatnya kurang jelas"},
+		{ID: "c2", ParentID: &c1, Body: "Sudah saya upload ulang"},
+		{ID: "c4", ParentID: &c3, Body: "balasan di thread internal yang disembunyikan"},
+		{ID: "c5", Body: "Terima kasih"},
+	}
+
+	threads := commentThreads(comments)
+	assert.Len(t, threads, 2)
+	assert.Equal(t, "c1", threads[0].ID)
+	assert.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, "c2", threads[0].Replies[0].ID)
+	assert.Equal(t, "c5", threads[1].ID)
+	assert.Empty(t, threads[1].Replies)
+}
+
+func TestHasAttachment(t *testing.T) {
+	doc := bson.M{"attachments": bson.A{
+		bson.M{"fileName": "sertifikat.pdf", "fileUrl": "uploads/s1/abc_sertifikat.pdf"},
+	}}
+	assert.True(t, hasAttachment(doc, "uploads/s1/abc_sertifikat.pdf"))
+	assert.False(t, hasAttachment(doc, "uploads/s2/lain.pdf"))
+	assert.False(t, hasAttachment(bson.M{}, "uploads/s1/abc_sertifikat.pdf"))
+}
+
+func TestComments_StrangerLecturerForbidden(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectReference(mock)
+	expectNotStudentNorAdvisor(mock, "dosen-lain")
+	expectPermissions(mock, "role-dosen", "achievement:comment")
+
+	app := newViewerApp(newViewerService(db), "dosen-lain", "role-dosen", []string{})
+	resp, err := app.Test(httptest.NewRequest("GET", "/achievements/a1/comments", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	// izin role sudah di-cache, jadi tidak di-query ulang
+	expectReference(mock)
+	expectNotStudentNorAdvisor(mock, "dosen-lain")
+
+	req := httptest.NewRequest("POST", "/achievements/a1/comments", strings.NewReader(`{"body":"halo"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestComments_StudentDoesNotSeeInternal(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	created := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	expectReference(mock)
+	mock.ExpectQuery(`SELECT id FROM students WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("s1"))
+	expectPermissions(mock, "role-mahasiswa", "achievement:comment")
+	mock.ExpectQuery(`FROM achievement_comments c[\s\S]+GROUP BY c.achievement_id`).
+		WithArgs("u1", sqlmock.AnyArg(), false).
+		WillReturnRows(sqlmock.NewRows([]string{"achievement_id", "count"}))
+	mock.ExpectQuery(`FROM achievement_comments c[\s\S]+c.internal = false OR \$2`).
+		WithArgs("a1", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "achievement_id", "parent_id", "author_id", "full_name", "name",
+			"body", "attachment_ref", "internal", "created_at"}).
+			AddRow("c1", "a1", nil, "dosen", "Pak Dosen", "Dosen Wali", "Sertifikatnya kurang jelas", nil, false, created))
+	// posisi baca = komentar terakhir yang dikirim, bukan NOW()
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO achievement_comment_reads`).
+		WithArgs("a1", "u1", created).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	service := newViewerService(db)
+	service.CommentRepo = repository.NewAchievementCommentRepository(db)
+	app := newViewerApp(service, "u1", "role-mahasiswa", []string{})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/achievements/a1/comments", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body model.AchievementCommentsResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Data, 1)
+	assert.False(t, body.Data[0].Internal)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddComment_MalformedParentID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectReference(mock)
+	mock.ExpectQuery(`SELECT id FROM students WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("s1"))
+	expectPermissions(mock, "role-mahasiswa", "achievement:comment")
+
+	service := newViewerService(db)
+	service.CommentRepo = repository.NewAchievementCommentRepository(db)
+	app := newViewerApp(service, "u1", "role-mahasiswa", []string{})
+
+	req := httptest.NewRequest("POST", "/achievements/a1/comments",
+		strings.NewReader(`{"body":"balasan","parent_id":"bukan-uuid"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// errPreconditionRequired: request yang mengubah dokumen wajib membawa If-Match
+var errPreconditionRequired = fiber.NewError(428, "header If-Match wajib diisi dengan ETag dari GET /achievements/:id")
+
+// achievementETag memformat versi dokumen sebagai strong ETag
+func achievementETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parseIfMatch membaca versi dari header If-Match. Weak ETag (W/"3") diterima
+// karena sebagian proxy mengubah strong ETag menjadi weak.
+func parseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, errPreconditionRequired
+	}
+
+	value := strings.TrimPrefix(header, "W/")
+	value = strings.Trim(value, `"`)
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 0 {
+		return 0, fiber.NewError(400, "If-Match tidak valid")
+	}
+	return version, nil
+}
+
+// docVersion membaca field version dokumen Mongo; dokumen lama tanpa field = 0
+func docVersion(doc bson.M) int {
+	switch v := doc["version"].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func versionMismatch(c *fiber.Ctx) error {
+	return c.Status(412).JSON(fiber.Map{
+		"message": "Prestasi sudah diubah di tempat lain, muat ulang lalu coba lagi",
+	})
+}
+
+func preconditionError(c *fiber.Ctx, err error) error {
+	if e, ok := err.(*fiber.Error); ok {
+		return c.Status(e.Code).JSON(fiber.Map{"message": e.Message})
+	}
+	return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-prestasi/app/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	v, err := parseIfMatch(`"3"`)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+
+	v, err = parseIfMatch(` W/"7" `)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, v)
+
+	_, err = parseIfMatch("")
+	assert.Equal(t, errPreconditionRequired, err)
+
+	_, err = parseIfMatch(`"abc"`)
+	assert.Error(t, err)
+	_, err = parseIfMatch(`"-1"`)
+	assert.Error(t, err)
+}
+
+func TestDocVersion(t *testing.T) {
+	assert.Equal(t, 4, docVersion(bson.M{"version": int32(4)}))
+	assert.Equal(t, 5, docVersion(bson.M{"version": int64(5)}))
+	// dokumen lama sebelum ada field version
+	assert.Equal(t, 0, docVersion(bson.M{}))
+	assert.Equal(t, `"2"`, achievementETag(2))
+}
+
+func TestUpdate_RequiresIfMatch(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id FROM students WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("s1"))
+
+	service := NewAchievementService(nil, repository.NewAchievementReferenceRepository(db), nil, nil, nil, nil, nil, nil, nil)
+	app := newAchievementApp(service, "u1")
+
+	req := httptest.NewRequest("PUT", "/achievements/a1", strings.NewReader(`{"title":"Baru"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 428, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// achievementPatchFields: field yang boleh diubah mahasiswa (nama JSON → nama di Mongo).
+// studentId, points, attachments, version, dan timestamp hanya diubah sistem.
+var achievementPatchFields = map[string]string{
+	"title":            "title",
+	"description":      "description",
+	"achievement_type": "achievementType",
+	"details":          "details",
+	"tags":             "tags",
+}
+
+// Patch godoc
+// @Summary Patch achievement draft
+// @Description Ubah sebagian prestasi dengan JSON Merge Patch (RFC 7396): field yang tidak dikirim
+// tidak berubah, null menghapus nilai, dan details digabung per key. Hanya title, description,
+// achievement_type, details, dan tags yang boleh diubah; details divalidasi ulang terhadap schema tipe.
+// If-Match wajib berisi ETag terakhir.
+// @Tags Achievement
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param If-Match header string true "ETag dari GET /achievements/:id"
+// @Param request body model.UpdateAchievementRequest true "Merge patch"
+// @Success 200 {object} model.UpdateAchievementResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
+// @Failure 415 {object} model.ErrorResponse
+// @Failure 422 {object} model.DetailsValidationResponse
+// @Failure 428 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/{id} [patch]
+func (s *AchievementService) Patch(c *fiber.Ctx) error {
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(c.Get(fiber.HeaderContentType), ";")[0]))
+	if contentType != mergePatchContentType && contentType != fiber.MIMEApplicationJSON {
+		return c.Status(415).JSON(fiber.Map{
+			"message": "Content-Type harus " + mergePatchContentType,
+		})
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(c.Body(), &raw); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Payload invalid",
+		})
+	}
+
+	// patch non-object akan mengganti seluruh dokumen, tidak diizinkan
+	patch, ok := raw.(map[string]interface{})
+	if !ok || len(patch) == 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Patch harus berupa object dan tidak boleh kosong",
+		})
+	}
+
+	if denied := disallowedPatchFields(patch); len(denied) > 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Field tidak boleh diubah: " + strings.Join(denied, ", "),
+		})
+	}
+
+	return s.applyAchievementPatch(c, patch, false)
+}
+
+// applyAchievementPatch dipakai PUT dan PATCH. PUT mengganti details secara utuh
+// (replaceDetails), PATCH menggabungkannya.
+func (s *AchievementService) applyAchievementPatch(c *fiber.Ctx, patch map[string]interface{}, replaceDetails bool) error {
+	id := c.Params("id")
+	userID := c.Locals("user_id").(string)
+
+	studentID, err := s.RefRepo.GetStudentIDByUser(userID)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"message": "Data mahasiswa tidak ditemukan",
+		})
+	}
+
+	version, err := parseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return preconditionError(c, err)
+	}
+
+	ref, err := s.RefRepo.GetEditableByOwner(id, studentID)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"message": "Prestasi tidak ditemukan, bukan milik Anda, atau tidak bisa diubah pada status ini",
+		})
+	}
+
+	current, err := s.MongoRepo.FindByID(ref.MongoAchievementID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"message": "Gagal membaca data prestasi",
+		})
+	}
+	if docVersion(current) != version {
+		return versionMismatch(c)
+	}
+
+	target := editableAchievement(current)
+	if replaceDetails {
+		if _, ok := patch["details"]; ok {
+			delete(target, "details")
+		}
+	}
+	merged, _ := mergePatch(target, patch).(map[string]interface{})
+
+	update, msg := normalizePatchedAchievement(merged, patch)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": msg,
+		})
+	}
+
+	// achievement_type / details divalidasi ulang terhadap schema tipe yang berlaku
+	_, hasType := patch["achievement_type"]
+	_, hasDetails := patch["details"]
+	if hasType || hasDetails {
+		typeCode := update["achievementType"].(string)
+
+		achievementType, err := s.TypeRepo.GetByCode(typeCode)
+		if err != nil && err != sql.ErrNoRows {
+			return c.Status(500).JSON(fiber.Map{
+				"message": "Gagal membaca jenis prestasi",
+			})
+		}
+		// pindah tipe hanya ke tipe yang masih aktif
+		changed := typeCode != current["achievementType"]
+		if err == sql.ErrNoRows || (changed && !achievementType.IsActive) {
+			return c.Status(400).JSON(fiber.Map{
+				"message": "achievement_type tidak dikenal atau sudah tidak aktif",
+			})
+		}
+
+		if errs := validateAchievementDetails(*achievementType, update["details"].(map[string]interface{}), false); len(errs) > 0 {
+			return c.Status(422).JSON(model.DetailsValidationResponse{
+				Error:  "details tidak valid",
+				Fields: errs,
+			})
+		}
+	}
+
+	// hanya field yang ada di patch yang ditulis
+	set := map[string]interface{}{"updatedAt": time.Now()}
+	for field := range patch {
+		name := achievementPatchFields[field]
+		set[name] = update[name]
+	}
+
+	newVersion, err := s.MongoRepo.UpdateByIDIfVersion(ref.MongoAchievementID, version, set)
+	if err == repository.ErrVersionConflict {
+		return versionMismatch(c)
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"message": "Gagal update data",
+		})
+	}
+
+	if snapshotEditsFromEnv() {
+		updated := bson.M{}
+		for k, v := range current {
+			updated[k] = v
+		}
+		for k, v := range set {
+			updated[k] = v
+		}
+		if err := s.saveSnapshot(ref.MongoAchievementID, updated, newVersion, model.SnapshotReasonEdit, userID); err != nil {
+			log.Println("snapshot edit prestasi gagal disimpan:", err)
+		}
+	}
+
+	c.Set(fiber.HeaderETag, achievementETag(newVersion))
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Draft berhasil diupdate",
+		"data": fiber.Map{
+			"id":       ref.ID,
+			"mongo_id": ref.MongoAchievementID,
+		},
+	})
+}
+
+// updateRequestPatch mengubah body PUT menjadi patch; field nil berarti tidak diubah
+func updateRequestPatch(req model.UpdateAchievementRequest) map[string]interface{} {
+	patch := map[string]interface{}{}
+	if req.AchievementType != nil {
+		patch["achievement_type"] = *req.AchievementType
+	}
+	if req.Title != nil {
+		patch["title"] = *req.Title
+	}
+	if req.Description != nil {
+		patch["description"] = *req.Description
+	}
+	if req.Details != nil {
+		patch["details"] = req.Details
+	}
+	if req.Tags != nil {
+		tags := make([]interface{}, 0, len(*req.Tags))
+		for _, t := range *req.Tags {
+			tags = append(tags, t)
+		}
+		patch["tags"] = tags
+	}
+	return patch
+}
+
+func disallowedPatchFields(patch map[string]interface{}) []string {
+	denied := []string{}
+	for field := range patch {
+		if _, ok := achievementPatchFields[field]; !ok {
+			denied = append(denied, field)
+		}
+	}
+	sort.Strings(denied)
+	return denied
+}
+
+// editableAchievement mengambil field yang bisa di-patch dari dokumen Mongo
+// dengan nama JSON dan tipe Go biasa
+func editableAchievement(doc bson.M) map[string]interface{} {
+	view := map[string]interface{}{}
+	for field, name := range achievementPatchFields {
+		if v, ok := doc[name]; ok && v != nil {
+			view[field] = plainValue(v)
+		}
+	}
+	return view
+}
+
+// plainValue mengubah bson.M / bson.A bersarang menjadi map dan slice biasa
+func plainValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case bson.M:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = plainValue(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = plainValue(item)
+		}
+		return out
+	case bson.A:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = plainValue(item)
+		}
+		return out
+	case primitive.D:
+		return plainValue(val.Map())
+	}
+	return v
+}
+
+// mergePatch menerapkan JSON Merge Patch (RFC 7396)
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+
+	return targetObj
+}
+
+// normalizePatchedAchievement memeriksa tipe hasil merge dan mengembalikan
+// nilai siap simpan dengan nama field Mongo. Pesan kosong berarti valid.
+func normalizePatchedAchievement(merged map[string]interface{}, patch map[string]interface{}) (map[string]interface{}, string) {
+	out := map[string]interface{}{}
+
+	title, _ := merged["title"].(string)
+	if _, touched := patch["title"]; touched && strings.TrimSpace(title) == "" {
+		return nil, "title wajib diisi"
+	}
+	out["title"] = title
+
+	description := ""
+	if v, ok := merged["description"]; ok {
+		str, isStr := v.(string)
+		if !isStr {
+			return nil, "description harus berupa teks"
+		}
+		description = str
+	}
+	out["description"] = description
+
+	typeCode, ok := merged["achievement_type"].(string)
+	if _, touched := patch["achievement_type"]; touched && (!ok || typeCode == "") {
+		return nil, "achievement_type tidak dikenal atau sudah tidak aktif"
+	}
+	out["achievementType"] = typeCode
+
+	details := map[string]interface{}{}
+	if v, ok := merged["details"]; ok {
+		d, isObj := v.(map[string]interface{})
+		if !isObj {
+			return nil, "details harus berupa object"
+		}
+		details = d
+	}
+	out["details"] = details
+
+	tags := []string{}
+	if v, ok := merged["tags"]; ok {
+		items, isArr := v.([]interface{})
+		if !isArr {
+			return nil, "tags harus berupa daftar teks"
+		}
+		for _, item := range items {
+			str, isStr := item.(string)
+			if !isStr {
+				return nil, "tags harus berupa daftar teks"
+			}
+			tags = append(tags, str)
+		}
+	}
+	out["tags"] = tags
+
+	return out, ""
+}
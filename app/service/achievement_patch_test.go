@@ -0,0 +1,107 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-prestasi/app/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMergePatch_RFC7396(t *testing.T) {
+	target := map[string]interface{}{
+		"title": "Lama",
+		"tags":  []interface{}{"a", "b"},
+		"details": map[string]interface{}{
+			"competitionName": "Gemastik",
+			"rank":            float64(2),
+		},
+	}
+	patch := map[string]interface{}{
+		"tags": []interface{}{"c"},
+		"details": map[string]interface{}{
+			"rank":             nil,
+			"competitionLevel": "national",
+		},
+	}
+
+	merged := mergePatch(target, patch).(map[string]interface{})
+	assert.Equal(t, "Lama", merged["title"])
+	// array diganti utuh, object digabung per key, null menghapus
+	assert.Equal(t, []interface{}{"c"}, merged["tags"])
+	assert.Equal(t, map[string]interface{}{
+		"competitionName":  "Gemastik",
+		"competitionLevel": "national",
+	}, merged["details"])
+}
+
+func TestDisallowedPatchFields(t *testing.T) {
+	denied := disallowedPatchFields(map[string]interface{}{
+		"title":       "x",
+		"studentId":   "s2",
+		"points":      100,
+		"attachments": nil,
+	})
+	assert.Equal(t, []string{"attachments", "points", "studentId"}, denied)
+}
+
+func TestEditableAchievement_PlainValues(t *testing.T) {
+	doc := bson.M{
+		"studentId":       "s1",
+		"title":           "Juara",
+		"achievementType": "competition",
+		"details":         bson.M{"teamMembers": bson.A{"Ani", "Budi"}},
+	}
+
+	view := editableAchievement(doc)
+	assert.NotContains(t, view, "studentId")
+	assert.Equal(t, "competition", view["achievement_type"])
+	assert.Equal(t, map[string]interface{}{"teamMembers": []interface{}{"Ani", "Budi"}}, view["details"])
+}
+
+func TestNormalizePatchedAchievement(t *testing.T) {
+	_, msg := normalizePatchedAchievement(map[string]interface{}{}, map[string]interface{}{"title": nil})
+	assert.Equal(t, "title wajib diisi", msg)
+
+	_, msg = normalizePatchedAchievement(map[string]interface{}{"title": "x", "details": "teks"},
+		map[string]interface{}{"details": "teks"})
+	assert.Equal(t, "details harus berupa object", msg)
+
+	out, msg := normalizePatchedAchievement(map[string]interface{}{"title": "x", "achievement_type": "academic"},
+		map[string]interface{}{"description": nil})
+	assert.Empty(t, msg)
+	assert.Equal(t, "", out["description"])
+	assert.Equal(t, []string{}, out["tags"])
+	assert.Equal(t, map[string]interface{}{}, out["details"])
+}
+
+func TestUpdateRequestPatch(t *testing.T) {
+	title := "Baru"
+	patch := updateRequestPatch(model.UpdateAchievementRequest{
+		Title: &title,
+		Tags:  &[]string{"ai"},
+	})
+	assert.Equal(t, map[string]interface{}{"title": "Baru", "tags": []interface{}{"ai"}}, patch)
+}
+
+func TestPatch_RejectsProtectedFields(t *testing.T) {
+	app := newAchievementApp(NewAchievementService(nil, nil, nil, nil, nil, nil, nil, nil, nil), "u1")
+
+	req := httptest.NewRequest("PATCH", "/achievements/a1", strings.NewReader(`{"title":"x","studentId":"s2"}`))
+	req.Header.Set("Content-Type", mergePatchContentType)
+	req.Header.Set("If-Match", `"1"`)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	req = httptest.NewRequest("PATCH", "/achievements/a1", strings.NewReader(`{"title":"x"}`))
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 415, resp.StatusCode)
+}
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"uas-prestasi/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// kandidat dari text index sebelum disaring status di Postgres
+	searchCandidateLimit = 200
+	// dokumen terbaru yang dinilai saat pencarian toleran salah ketik
+	fuzzyScanLimit = 1000
+	// jumlah karakter konteks di kiri potongan highlight
+	snippetContext = 40
+)
+
+// Search godoc
+// @Summary Search achievements
+// @Description Pencarian teks di title, description, tags, dan field teks details, diurutkan
+// berdasarkan relevansi. Kalau tidak ada hasil persis, dicoba pencocokan toleran salah ketik
+// (meta.fuzzy=true). Hasil dibatasi sesuai role seperti GET /achievements.
+// meta.total dihitung dari kandidat yang dibatasi; meta.total_capped=true berarti hasil sebenarnya bisa lebih banyak.
+// Highlight berisi teks yang sudah di-escape dengan kata cocok dibungkus <mark>.
+// @Tags Achievement
+// @Produce json
+// @Param q query string true "Kata kunci (minimal 2 karakter)"
+// @Param limit query int false "Jumlah hasil" default(20)
+// @Success 200 {object} model.AchievementSearchResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/search [get]
+func (s *AchievementService) Search(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+	roleID := c.Locals("role_id").(string)
+
+	query := strings.TrimSpace(c.Query("q"))
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return c.Status(400).JSON(fiber.Map{"message": "q minimal 2 karakter"})
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	// ===== Role-based scope, sama dengan List =====
+	var (
+		studentIDs []string // nil = semua mahasiswa
+		hideDrafts bool
+		err        error
+	)
+	if ok, _ := s.PermissionService.HasPermission(roleID, "achievement:list:all"); ok {
+		if scope := orgScope(c); scope != nil {
+			studentIDs, err = s.RefRepo.GetStudentIDsInPrograms(scope)
+		}
+	} else if ok, _ := s.PermissionService.HasPermission(roleID, "achievement:list:advisor"); ok {
+		studentIDs, err = s.RefRepo.GetAdviseeStudentIDs(userID)
+		hideDrafts = true
+	} else if ok, _ := s.PermissionService.HasPermission(roleID, "achievement:list:self"); ok {
+		studentID, err2 := s.RefRepo.GetStudentIDByUser(userID)
+		if err2 != nil {
+			return c.Status(403).JSON(fiber.Map{"message": "Student data not found"})
+		}
+		studentIDs = []string{studentID}
+	} else {
+		return c.Status(403).JSON(fiber.Map{
+			"message": "You do not have permission to access this resource",
+		})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "failed to resolve search scope"})
+	}
+
+	resp := model.AchievementSearchResponse{Status: "success", Data: []model.AchievementSearchHit{}}
+	resp.Meta.Query = query
+
+	if studentIDs != nil && len(studentIDs) == 0 {
+		return c.JSON(resp)
+	}
+
+	docs, err := s.MongoRepo.TextSearch(query, studentIDs, searchCandidateLimit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "failed to search achievements"})
+	}
+	resp.Meta.TotalCapped = len(docs) >= searchCandidateLimit
+
+	if len(docs) == 0 {
+		recent, err := s.MongoRepo.FindRecent(studentIDs, fuzzyScanLimit)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "failed to search achievements"})
+		}
+		docs = rankFuzzy(recent, terms)
+		resp.Meta.Fuzzy = true
+		resp.Meta.TotalCapped = len(recent) >= fuzzyScanLimit
+	}
+
+	mongoIDs := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		mongoIDs = append(mongoIDs, docHexID(doc))
+	}
+
+	refs, err := s.RefRepo.GetByMongoIDs(mongoIDs)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "failed to load achievements"})
+	}
+
+	resp.Data, resp.Meta.Total = searchHits(docs, refs, studentIDs, hideDrafts, terms, limit)
+
+	return c.JSON(resp)
+}
+
+// searchHits menyaring kandidat dokumen dengan data Postgres (status dan scope
+// mahasiswa) lalu menyusun hasil. studentIDs nil = semua mahasiswa.
+func searchHits(docs []bson.M, refs map[string]model.AchievementReference, studentIDs []string,
+	hideDrafts bool, terms []string, limit int) ([]model.AchievementSearchHit, int) {
+	var allowed map[string]bool
+	if studentIDs != nil {
+		allowed = make(map[string]bool, len(studentIDs))
+		for _, id := range studentIDs {
+			allowed[id] = true
+		}
+	}
+
+	hits := []model.AchievementSearchHit{}
+	total := 0
+	for _, doc := range docs {
+		ref, ok := refs[docHexID(doc)]
+		if !ok || ref.Status == model.StatusDeleted || (hideDrafts && ref.Status == model.StatusDraft) {
+			continue
+		}
+		// scope dicek ulang terhadap student_id di Postgres, studentId di dokumen
+		// Mongo bisa saja berbeda (lihat rekonsiliasi)
+		if allowed != nil && !allowed[ref.StudentID] {
+			continue
+		}
+
+		total++
+		if len(hits) >= limit {
+			continue
+		}
+
+		score, _ := doc["score"].(float64)
+		typeCode, _ := doc["achievementType"].(string)
+		title, _ := doc["title"].(string)
+
+		hit := model.AchievementSearchHit{
+			ID:              ref.ID,
+			MongoID:         ref.MongoAchievementID,
+			StudentID:       ref.StudentID,
+			Status:          ref.Status,
+			AchievementType: typeCode,
+			Title:           title,
+			Score:           score,
+			Highlights:      map[string]string{},
+		}
+		for _, f := range searchableFields(doc) {
+			if snippet, ok := highlight(f.text, terms); ok {
+				hit.Highlights[f.name] = snippet
+			}
+		}
+
+		hits = append(hits, hit)
+	}
+
+	return hits, total
+}
+
+type searchField struct {
+	name   string
+	text   string
+	weight float64
+}
+
+// searchableFields mengambil field teks dokumen sesuai text index
+func searchableFields(doc bson.M) []searchField {
+	fields := []searchField{}
+
+	if v, ok := doc["title"].(string); ok && v != "" {
+		fields = append(fields, searchField{"title", v, 3})
+	}
+	if tags := stringValues(doc["tags"]); len(tags) > 0 {
+		fields = append(fields, searchField{"tags", strings.Join(tags, ", "), 2})
+	}
+	if v, ok := doc["description"].(string); ok && v != "" {
+		fields = append(fields, searchField{"description", v, 1})
+	}
+
+	details, _ := doc["details"].(bson.M)
+	for _, name := range model.SearchableDetailFields {
+		if values := stringValues(details[name]); len(values) > 0 {
+			fields = append(fields, searchField{"details." + name, strings.Join(values, ", "), 1.5})
+		}
+	}
+
+	return fields
+}
+
+// stringValues menerima string tunggal atau array string dari bson
+func stringValues(v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		if val != "" {
+			return []string{val}
+		}
+	case bson.A:
+		out := []string{}
+		for _, item := range val {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return val
+	}
+	return nil
+}
+
+func docHexID(doc bson.M) string {
+	if id, ok := doc["_id"].(primitive.ObjectID); ok {
+		return id.Hex()
+	}
+	return fmt.Sprint(doc["_id"])
+}
+
+// searchTerms memecah query menjadi kata kecil unik. Kata dengan awalan "-"
+// (pengecualian di sintaks $text) tidak dipakai untuk highlight.
+func searchTerms(query string) []string {
+	terms := []string{}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Fields(query) {
+		if strings.HasPrefix(part, "-") {
+			continue
+		}
+		for _, t := range tokenize(part) {
+			if len([]rune(t)) < 2 || seen[t] {
+				continue
+			}
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+
+	return terms
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isWordRune(r) })
+}
+
+// termMatch menilai kecocokan satu kata query dengan satu kata dokumen:
+// sama persis 1, awalan 0.8, salah ketik 1 huruf 0.6, 2 huruf 0.4 (kata ≥ 8 huruf)
+func termMatch(term, word string) float64 {
+	if term == word {
+		return 1
+	}
+
+	termLen := len([]rune(term))
+	if termLen >= 3 && strings.HasPrefix(word, term) {
+		return 0.8
+	}
+
+	maxDist := 0
+	switch {
+	case termLen >= 8:
+		maxDist = 2
+	case termLen >= 4:
+		maxDist = 1
+	}
+	if maxDist == 0 {
+		return 0
+	}
+
+	dist := levenshtein(term, word, maxDist)
+	switch {
+	case dist > maxDist:
+		return 0
+	case dist == 1:
+		return 0.6
+	case dist == 2:
+		return 0.4
+	}
+	return 0
+}
+
+// levenshtein menghitung edit distance, berhenti lebih awal kalau sudah > max
+// (nilai kembali max+1)
+func levenshtein(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+
+	if prev[len(rb)] > max {
+		return max + 1
+	}
+	return prev[len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// rankFuzzy menilai dokumen di aplikasi saat text index tidak menemukan apa pun.
+// Skor = jumlah kecocokan terbaik tiap kata query × bobot field, disimpan di "score".
+func rankFuzzy(docs []bson.M, terms []string) []bson.M {
+	ranked := []bson.M{}
+
+	for _, doc := range docs {
+		fields := searchableFields(doc)
+		score := 0.0
+
+		for _, term := range terms {
+			best := 0.0
+			for _, f := range fields {
+				for _, word := range tokenize(f.text) {
+					if m := termMatch(term, word) * f.weight; m > best {
+						best = m
+					}
+				}
+			}
+			score += best
+		}
+
+		if score > 0 {
+			doc["score"] = score
+			ranked = append(ranked, doc)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i]["score"].(float64) > ranked[j]["score"].(float64)
+	})
+
+	return ranked
+}
+
+// highlight membuat potongan teks di sekitar kata cocok pertama. Teks di-escape
+// HTML lebih dulu supaya hanya <mark> yang menjadi markup.
+func highlight(text string, terms []string) (string, bool) {
+	runes := []rune(text)
+
+	type span struct{ start, end int }
+	spans := []span{}
+
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+
+		word := strings.ToLower(string(runes[i:j]))
+		for _, t := range terms {
+			if termMatch(t, word) > 0 {
+				spans = append(spans, span{i, j})
+				break
+			}
+		}
+		i = j
+	}
+
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	start := max(spans[0].start-snippetContext, 0)
+	end := min(spans[0].end+2*snippetContext, len(runes))
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+
+	pos := start
+	for _, sp := range spans {
+		if sp.end > end {
+			break
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:sp.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[sp.start:sp.end])))
+		b.WriteString("</mark>")
+		pos = sp.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String(), true
+}
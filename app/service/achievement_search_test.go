@@ -0,0 +1,89 @@
+package service
+
+import (
+	"testing"
+
+	"uas-prestasi/app/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"machine", "learning"}, searchTerms(`"Machine Learning" -robot machine`))
+	assert.Empty(t, searchTerms("a"))
+}
+
+func TestTermMatch(t *testing.T) {
+	assert.Equal(t, 1.0, termMatch("learning", "learning"))
+	assert.Equal(t, 0.8, termMatch("learn", "learning"))
+	assert.Equal(t, 0.6, termMatch("machne", "machine"))
+	assert.Equal(t, 0.4, termMatch("pubilkasi", "publikasi"))
+	// kata pendek tidak ditoleransi salah ketik
+	assert.Equal(t, 0.0, termMatch("ai", "al"))
+	assert.Equal(t, 0.0, termMatch("robot", "rocket"))
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("abc", "abc", 2))
+	assert.Equal(t, 1, levenshtein("kompetisi", "kompetsi", 2))
+	assert.Equal(t, 3, levenshtein("abc", "xyz", 2))
+	assert.Equal(t, 3, levenshtein("a", "abcd", 2))
+}
+
+func TestHighlight(t *testing.T) {
+	snippet, ok := highlight("Penerapan <b>Machine</b> Learning untuk deteksi", []string{"machine", "learning"})
+	assert.True(t, ok)
+	assert.Equal(t, "Penerapan &lt;b&gt;<mark>Machine</mark>&lt;/b&gt; <mark>Learning</mark> untuk deteksi", snippet)
+
+	long := "Lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor robotika " +
+		"incididunt ut labore et dolore magna aliqua ut enim ad minim veniam quis nostrud exercitation"
+	snippet, ok = highlight(long, []string{"robotika"})
+	assert.True(t, ok)
+	assert.Contains(t, snippet, "<mark>robotika</mark>")
+	assert.True(t, len(snippet) < len(long))
+	assert.Equal(t, "…", string([]rune(snippet)[0]))
+
+	_, ok = highlight("tidak ada", []string{"robotika"})
+	assert.False(t, ok)
+}
+
+func TestRankFuzzy(t *testing.T) {
+	docs := []bson.M{
+		{"title": "Juara 2 Lomba Robotik", "description": "machine vision"},
+		{"title": "Publikasi Machine Learning", "tags": bson.A{"ai"}},
+		{"title": "Sertifikasi Cloud"},
+	}
+
+	ranked := rankFuzzy(docs, []string{"machne", "lerning"})
+
+	assert.Len(t, ranked, 2)
+	assert.Equal(t, "Publikasi Machine Learning", ranked[0]["title"])
+	assert.Greater(t, ranked[0]["score"].(float64), ranked[1]["score"].(float64))
+}
+
+func TestSearchHits_ScopedByPostgresStudent(t *testing.T) {
+	docs := []bson.M{
+		{"_id": "m1", "studentId": "s1", "title": "Lomba Robotik"},
+		// studentId di Mongo masih dalam scope, tetapi di Postgres milik mahasiswa lain
+		{"_id": "m2", "studentId": "s1", "title": "Robotik Nasional"},
+		{"_id": "m3", "studentId": "s1", "title": "Robotik Draft"},
+		{"_id": "m4", "studentId": "s1", "title": "Robotik Regional"},
+	}
+	refs := map[string]model.AchievementReference{
+		"m1": {ID: "a1", StudentID: "s1", MongoAchievementID: "m1", Status: model.StatusVerified},
+		"m2": {ID: "a2", StudentID: "s9", MongoAchievementID: "m2", Status: model.StatusVerified},
+		"m3": {ID: "a3", StudentID: "s1", MongoAchievementID: "m3", Status: model.StatusDraft},
+		"m4": {ID: "a4", StudentID: "s1", MongoAchievementID: "m4", Status: model.StatusSubmitted},
+	}
+
+	hits, total := searchHits(docs, refs, []string{"s1"}, true, []string{"robotik"}, 1)
+	assert.Equal(t, 2, total)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, "a1", hits[0].ID)
+	assert.Equal(t, "Lomba <mark>Robotik</mark>", hits[0].Highlights["title"])
+
+	// tanpa scope (super admin) semua reference ikut
+	_, total = searchHits(docs, refs, nil, false, []string{"robotik"}, 10)
+	assert.Equal(t, 4, total)
+}
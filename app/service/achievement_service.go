@@ -2,18 +2,23 @@ package service
 
 import (
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"log"
 	"math"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
-
 	"uas-prestasi/app/model"
 	"uas-prestasi/app/repository"
+	"uas-prestasi/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type AchievementService struct {
@@ -22,13 +27,21 @@ type AchievementService struct {
 	StudentDB         *sql.DB
 	PermissionService *PermissionService
 	PermissionRepo    *repository.PermissionRepository
+	TypeRepo          *repository.AchievementTypeRepository
+	Outbox            *OutboxDispatcher
+	VersionRepo       *repository.AchievementVersionRepository
+	CommentRepo       *repository.AchievementCommentRepository
 }
 
 func NewAchievementService(mongoRepo *repository.AchievementMongoRepository,
 	refRepo *repository.AchievementReferenceRepository,
 	studentDB *sql.DB,
 	permissionService *PermissionService,
-	permissionRepo *repository.PermissionRepository) *AchievementService {
+	permissionRepo *repository.PermissionRepository,
+	typeRepo *repository.AchievementTypeRepository,
+	outbox *OutboxDispatcher,
+	versionRepo *repository.AchievementVersionRepository,
+	commentRepo *repository.AchievementCommentRepository) *AchievementService {
 
 	return &AchievementService{
 		MongoRepo:         mongoRepo,
@@ -36,13 +49,18 @@ func NewAchievementService(mongoRepo *repository.AchievementMongoRepository,
 		StudentDB:         studentDB,
 		PermissionService: permissionService,
 		PermissionRepo:    permissionRepo,
+		TypeRepo:          typeRepo,
+		Outbox:            outbox,
+		VersionRepo:       versionRepo,
+		CommentRepo:       commentRepo,
 	}
 }
 
 // CreateDraft godoc
 // @Summary Create achievement draft
 // @Description Create draft prestasi.
-// Details bersifat dinamis tergantung achievement_type.
+// Details divalidasi sesuai schema achievement_type (lihat GET /achievements/schemas).
+// Field wajib baru dicek saat submit, tipe/enum/format tanggal dicek sejak draft.
 // Contoh competition: { competitionName, competitionLevel, rank, medalType }
 // @Tags Achievement
 // @Accept json
@@ -50,6 +68,7 @@ func NewAchievementService(mongoRepo *repository.AchievementMongoRepository,
 // @Param request body model.CreateAchievementRequest true "Create draft payload"
 // @Success 200 {object} model.CreateDraftResponse
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 422 {object} model.DetailsValidationResponse
 // @Failure 403 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -67,55 +86,90 @@ func (s *AchievementService) CreateDraft(c *fiber.Ctx) error {
 		return c.Status(403).JSON(fiber.Map{"error": "user is not a student"})
 	}
 
+	if strings.TrimSpace(req.Title) == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "title wajib diisi"})
+	}
+
+	achievementType, err := s.TypeRepo.GetByCode(req.AchievementType)
+	if err == sql.ErrNoRows || (err == nil && !achievementType.IsActive) {
+		return c.Status(400).JSON(fiber.Map{"error": "achievement_type tidak dikenal atau sudah tidak aktif"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "failed to load achievement type"})
+	}
+
+	if req.Details == nil {
+		req.Details = map[string]interface{}{}
+	}
+	if errs := validateAchievementDetails(*achievementType, req.Details, false); len(errs) > 0 {
+		return c.Status(422).JSON(model.DetailsValidationResponse{
+			Error:  "details tidak valid",
+			Fields: errs,
+		})
+	}
+
 	achievement := &model.Achievement{
 		StudentID:       studentID,
 		AchievementType: req.AchievementType,
 		Title:           req.Title,
 		Description:     req.Description,
 		Details:         req.Details,
-		Points:          req.Points,
+		Points:          0, // poin hanya diisi saat verifikasi
 		Tags:            req.Tags,
 		Attachments:     []model.Attachment{},
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
+		Version:         1,
 	}
 
-	mongoID, err := s.MongoRepo.InsertDraft(achievement)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "failed to insert draft"})
-	}
+	// _id Mongo ditentukan di sini supaya reference dan event outbox
+	// bisa ditulis dalam satu transaksi Postgres sebelum dokumennya ada
+	mongoID := primitive.NewObjectID().Hex()
 
 	ref := &model.AchievementReference{
 		StudentID:          studentID,
 		MongoAchievementID: mongoID,
-		Status:             "draft",
+		Status:             model.StatusDraft,
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
 
-	if err := s.RefRepo.InsertDraft(ref); err != nil {
-		_ = s.MongoRepo.DeleteByID(mongoID)
+	eventID, err := s.RefRepo.InsertDraft(ref, userID, achievement)
+	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "failed to save reference"})
 	}
 
+	if err := s.Outbox.DispatchOne(eventID); err != nil {
+		log.Println("outbox: draft akan dibuat ulang oleh dispatcher:", err)
+	}
+
+	c.Set(fiber.HeaderETag, achievementETag(achievement.Version))
 	return c.JSON(fiber.Map{
 		"status": "success",
 		"data": fiber.Map{
 			"mongo_id": mongoID,
-			"status":   "draft",
+			"status":   model.StatusDraft,
 		},
 	})
 }
 
 // Submit godoc
 // @Summary Submit achievement
-// @Description Submit draft prestasi menjadi submitted
+// @Description Submit draft prestasi menjadi submitted. Prestasi yang ditolak bisa diajukan ulang
+// sampai batas ACHIEVEMENT_MAX_RESUBMISSIONS; setiap pengajuan tercatat terpisah di history.
+// If-Match opsional; kalau dikirim, submit ditolak 412 bila dokumen sudah berubah. Dokumen yang
+// diubah bersamaan saat submit juga menghasilkan 412 dan status tidak berubah.
+// Submit yang gagal di tengah jalan tetap bisa menaikkan versi dokumen (ETag berubah).
 // @Tags Achievement
 // @Produce json
 // @Param id path string true "Achievement ID"
+// @Param If-Match header string false "ETag dari GET /achievements/:id"
 // @Success 200 {object} model.SubmitAchievementResponse
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 422 {object} model.DetailsValidationResponse
 // @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/achievements/{id}/submit [post]
@@ -130,31 +184,134 @@ func (s *AchievementService) Submit(c *fiber.Ctx) error {
 		})
 	}
 
-	err = s.RefRepo.SubmitDraft(achievementID, studentID)
+	ref, err := s.RefRepo.GetEditableByOwner(achievementID, studentID)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "achievement not found or not in a submittable status",
+		})
+	}
+
+	// jatah pengajuan ulang hanya berkurang karena penolakan, bukan permintaan revisi
+	if ref.Status == model.StatusRejected && ref.RejectionCount > maxResubmissionsFromEnv() {
+		return c.Status(409).JSON(fiber.Map{
+			"error": "batas pengajuan ulang sudah tercapai",
+		})
+	}
+
+	achievement, err := s.MongoRepo.FindByID(ref.MongoAchievementID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "failed to load achievement",
+		})
+	}
+
+	version := docVersion(achievement)
+	if c.Get(fiber.HeaderIfMatch) != "" {
+		expected, err := parseIfMatch(c.Get(fiber.HeaderIfMatch))
+		if err != nil {
+			return preconditionError(c, err)
+		}
+		if expected != version {
+			return versionMismatch(c)
+		}
+	}
+
+	// saat submit semua field wajib harus sudah terisi
+	// draft lama dengan tipe yang sudah nonaktif tetap boleh disubmit
+	typeCode, _ := achievement["achievementType"].(string)
+	achievementType, err := s.TypeRepo.GetByCode(typeCode)
+	if err != nil && err != sql.ErrNoRows {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "failed to load achievement type",
+		})
+	}
+	if err == sql.ErrNoRows {
+		return c.Status(422).JSON(model.DetailsValidationResponse{
+			Error:  "details tidak valid",
+			Fields: []model.DetailFieldError{{Field: "achievement_type", Message: "tipe tidak dikenal"}},
+		})
+	}
+
+	details := map[string]interface{}{}
+	if d, ok := achievement["details"].(bson.M); ok {
+		details = d
+	}
+	if errs := validateAchievementDetails(*achievementType, details, true); len(errs) > 0 {
+		return c.Status(422).JSON(model.DetailsValidationResponse{
+			Error:  "details belum lengkap",
+			Fields: errs,
+		})
+	}
+
+	suggestedPoints := calculatePoints(*achievementType, details)
+
+	// versi dinaikkan setelah status berubah, sebelum transaksi commit: edit yang
+	// divalidasi dengan versi lama gagal, dan submit dibatalkan kalau ada edit
+	// di antara baca dan submit
+	newVersion := version + 1
+	snapshotSaved := false
+	submissionNo, err := s.RefRepo.SubmitDraft(achievementID, studentID, userID, suggestedPoints, newVersion, func() error {
+		if _, err := s.MongoRepo.BumpVersion(ref.MongoAchievementID, version); err != nil {
+			return err
+		}
+		// setiap pengajuan harus punya salinan dokumennya
+		if err := s.saveSnapshot(ref.MongoAchievementID, achievement, newVersion, model.SnapshotReasonSubmit, userID); err != nil {
+			return err
+		}
+		snapshotSaved = true
+		return nil
+	})
+	// commit gagal setelah snapshot tersimpan → buang snapshot-nya supaya tidak
+	// ada versi pengajuan yang statusnya tidak pernah berubah. Versi Mongo yang
+	// sudah dinaikkan sengaja tidak diturunkan lagi: isi dokumen tidak berubah,
+	// client cukup membaca ulang ETag, sedangkan menurunkan versi bisa membuat
+	// If-Match yang sudah ditolak kembali diterima.
+	if err != nil && snapshotSaved && s.VersionRepo != nil {
+		if delErr := s.VersionRepo.Delete(ref.MongoAchievementID, newVersion); delErr != nil {
+			log.Printf("submit %s: gagal menghapus snapshot versi %d: %v", achievementID, newVersion, delErr)
+		}
+	}
 	if err == sql.ErrNoRows {
 		return c.Status(400).JSON(fiber.Map{
-			"error": "achievement not found or not in draft status",
+			"error": "achievement not found or not in a submittable status",
 		})
 	}
+	if err == repository.ErrVersionConflict {
+		return versionMismatch(c)
+	}
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error": "failed to submit achievement",
 		})
 	}
 
+	c.Set(fiber.HeaderETag, achievementETag(newVersion))
 	return c.JSON(fiber.Map{
 		"status":  "success",
 		"message": "achievement submitted successfully",
 		"data": fiber.Map{
-			"id":     achievementID,
-			"status": "submitted",
+			"id":               achievementID,
+			"status":           model.StatusSubmitted,
+			"suggested_points": suggestedPoints,
+			"submission_no":    submissionNo,
 		},
 	})
 }
 
+const defaultMaxResubmissions = 3
+
+// batas pengajuan ulang setelah ditolak, bisa diatur lewat ACHIEVEMENT_MAX_RESUBMISSIONS
+func maxResubmissionsFromEnv() int {
+	if v, err := strconv.Atoi(os.Getenv("ACHIEVEMENT_MAX_RESUBMISSIONS")); err == nil && v >= 0 {
+		return v
+	}
+	return defaultMaxResubmissions
+}
+
 // Verify godoc
 // @Summary Verify achievement
-// @Description Dosen wali memverifikasi prestasi mahasiswa
+// @Description Dosen wali memverifikasi prestasi mahasiswa.
+// Poin default = poin usulan sistem saat submit; kalau points diisi berbeda, override_reason wajib.
 // @Tags Achievement
 // @Produce json
 // @Param id path string true "Achievement ID" 
@@ -178,7 +335,7 @@ func (s *AchievementService) Verify(c *fiber.Ctx) error {
 		})
 	}
 
-	if body.Points < 0 {
+	if body.Points != nil && *body.Points < 0 {
 		return c.Status(400).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Points tidak valid",
@@ -200,34 +357,63 @@ func (s *AchievementService) Verify(c *fiber.Ctx) error {
 		})
 	}
 
-	// Update status di Postgres
-	if err := s.RefRepo.Verify(achievementID, lecturerID); err != nil {
-		return c.Status(500).JSON(fiber.Map{
+	awarded, overrideReason, msg := resolveAwardedPoints(ref.SuggestedPoints, body)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal memverifikasi prestasi",
+			"message": msg,
 		})
 	}
 
-	// Update points di MongoDB
-	if err := s.MongoRepo.UpdatePoints(ref.MongoAchievementID, body.Points); err != nil {
+	// Update status di Postgres, points di MongoDB menyusul lewat outbox
+	eventID, err := s.RefRepo.Verify(achievementID, lecturerID, awarded, overrideReason)
+	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal mengupdate points di MongoDB",
+			"message": "Gagal memverifikasi prestasi",
 		})
 	}
 
+	if err := s.Outbox.DispatchOne(eventID); err != nil {
+		log.Println("outbox: points akan dikirim ulang oleh dispatcher:", err)
+	}
+
 	// Return langsung tanpa DTO
 	return c.JSON(fiber.Map{
 		"status":  "success",
 		"message": "Prestasi berhasil diverifikasi",
 		"data": fiber.Map{
-			"id":     achievementID,
-			"status": "verified",
-			"points": body.Points,
+			"id":                     achievementID,
+			"status":                 model.StatusVerified,
+			"points":                 awarded,
+			"suggested_points":       ref.SuggestedPoints,
+			"points_override_reason": overrideReason,
 		},
 	})
 }
 
+// resolveAwardedPoints menentukan poin akhir verifikasi. Tanpa input dosen
+// dipakai poin usulan; input yang berbeda dari usulan wajib disertai alasan.
+func resolveAwardedPoints(suggested *int, body model.VerifyAchievementRequest) (int, *string, string) {
+	if body.Points == nil {
+		if suggested == nil {
+			return 0, nil, "Points wajib diisi karena prestasi ini belum memiliki poin usulan"
+		}
+		return *suggested, nil, ""
+	}
+
+	if suggested != nil && *body.Points == *suggested {
+		return *body.Points, nil, ""
+	}
+
+	reason := strings.TrimSpace(body.OverrideReason)
+	if reason == "" {
+		return 0, nil, "override_reason wajib diisi jika poin berbeda dari usulan"
+	}
+
+	return *body.Points, &reason, ""
+}
+
 // Reject godoc
 // @Summary Reject achievement
 // @Description Dosen wali menolak prestasi dengan catatan
@@ -287,7 +473,131 @@ func (s *AchievementService) Reject(c *fiber.Ctx) error {
 		"message": "Prestasi berhasil ditolak",
 		"data": fiber.Map{
 			"id":     achievementID,
-			"status": "rejected",
+			"status": model.StatusRejected,
+		},
+	})
+}
+
+// Withdraw godoc
+// @Summary Withdraw submitted achievement
+// @Description Mahasiswa menarik kembali pengajuan menjadi draft selama dosen wali belum mulai memeriksa
+// (belum membuka detail). Tercatat di history dan dosen wali mendapat notifikasi.
+// @Tags Achievement
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} model.AchievementStatusResponse
+// @Failure 403 {object} model.MessageResponse
+// @Failure 404 {object} model.MessageResponse
+// @Failure 409 {object} model.MessageResponse
+// @Failure 500 {object} model.MessageResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/{id}/withdraw [post]
+func (s *AchievementService) Withdraw(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("user_id").(string)
+
+	studentID, err := s.RefRepo.GetStudentIDByUser(userID)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"message": "Data mahasiswa tidak ditemukan",
+		})
+	}
+
+	ref, err := s.RefRepo.GetOwnedAchievement(id, studentID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"message": "Achievement tidak ditemukan atau bukan milik Anda",
+		})
+	}
+
+	if !model.CanTransition(model.ActionWithdraw, ref.Status) {
+		return c.Status(409).JSON(fiber.Map{
+			"message": "Hanya prestasi berstatus submitted yang dapat ditarik",
+		})
+	}
+	if ref.ReviewStartedAt != nil {
+		return c.Status(409).JSON(fiber.Map{
+			"message": "Dosen wali sudah mulai memeriksa prestasi ini",
+		})
+	}
+
+	err = s.RefRepo.Withdraw(id, studentID, userID)
+	if err == sql.ErrNoRows {
+		// keduluan dosen wali di antara pengecekan dan update
+		return c.Status(409).JSON(fiber.Map{
+			"message": "Prestasi sudah mulai diperiksa atau sudah diproses",
+		})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"message": "Gagal menarik pengajuan",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pengajuan ditarik kembali menjadi draft",
+		"data": fiber.Map{
+			"id":     id,
+			"status": model.StatusDraft,
+		},
+	})
+}
+
+// RequestRevision godoc
+// @Summary Request achievement revision
+// @Description Dosen wali mengembalikan prestasi untuk dilengkapi (mis. sertifikat kurang).
+// Tidak dihitung sebagai penolakan; mahasiswa memperbaiki lalu submit ulang.
+// @Tags Achievement
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param request body model.RejectionNote true "Catatan revisi"
+// @Success 200 {object} model.AchievementStatusResponse
+// @Failure 400 {object} model.MessageResponse
+// @Failure 404 {object} model.MessageResponse
+// @Failure 500 {object} model.MessageResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/{id}/request-revision [post]
+func (s *AchievementService) RequestRevision(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	lecturerID := c.Locals("user_id").(string)
+
+	var req model.RejectionNote
+	if err := c.BodyParser(&req); err != nil || strings.TrimSpace(req.Note) == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Catatan revisi wajib diisi",
+		})
+	}
+
+	_, err := s.RefRepo.GetForVerification(achievementID, lecturerID)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Prestasi tidak ditemukan atau bukan bimbingan Anda",
+		})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal memvalidasi prestasi",
+		})
+	}
+
+	if err := s.RefRepo.RequestRevision(achievementID, lecturerID, strings.TrimSpace(req.Note)); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal meminta revisi prestasi",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Prestasi dikembalikan untuk direvisi",
+		"data": fiber.Map{
+			"id":     achievementID,
+			"status": model.StatusRevisionRequested,
 		},
 	})
 }
@@ -300,11 +610,30 @@ func (s *AchievementService) Reject(c *fiber.Ctx) error {
 //
 // @Param page query int false "Nomor halaman" default(1)
 // @Param limit query int false "Jumlah data per halaman" default(10)
-// @Param sort query string false "Field sorting (created_at, status)" default(created_at)
+// @Param sort query string false "Field sorting (created_at, submitted_at, status)" default(created_at)
 // @Param order query string false "Urutan sorting (asc | desc)" default(desc)
-// @Param status query string false "Filter status prestasi (draft, submitted, verified, rejected)"
+// @Param status query string false "Filter status prestasi (draft, submitted, verified, rejected, revision_requested)"
+// @Param student_id query string false "Admin/dosen wali: filter satu mahasiswa"
+// @Param advisor_id query string false "Admin: filter mahasiswa bimbingan satu dosen wali (lecturers.id)"
+// @Param type query string false "Filter jenis prestasi (achievement_type)"
+// @Param tags query string false "Filter tags, dipisah koma (semua harus ada)"
+// @Param competition_level query string false "Filter details.competitionLevel"
+// @Param q query string false "Cari kata di title, description, tags, dan details (text index)"
+// @Param created_from query string false "Tanggal dibuat mulai (YYYY-MM-DD)"
+// @Param created_to query string false "Tanggal dibuat sampai (YYYY-MM-DD, inklusif)"
+// @Param submitted_from query string false "Tanggal pengajuan mulai (YYYY-MM-DD), alias: from"
+// @Param submitted_to query string false "Tanggal pengajuan sampai (YYYY-MM-DD, inklusif), alias: to"
+// @Param verified_from query string false "Tanggal verifikasi mulai (YYYY-MM-DD)"
+// @Param verified_to query string false "Tanggal verifikasi sampai (YYYY-MM-DD, inklusif)"
+// @Param points_min query int false "Poin akhir minimal"
+// @Param points_max query int false "Poin akhir maksimal"
+// @Param pagination query string false "Isi cursor untuk pagination keyset (meta.next_cursor, tanpa total_pages)"
+// @Param cursor query string false "Cursor dari meta.next_cursor halaman sebelumnya; sort & order mengikuti cursor"
+// @Param with_total query bool false "Mode cursor: ikut hitung total (lebih lambat)"
+// @Param fields query string false "Field dokumen Mongo yang dikirim, dipisah koma (mis. title,achievementType,points). Kosong = lengkap"
 //
 // @Success 200 {object} map[string]interface{} "List achievements dengan pagination dan meta"
+// @Failure 400 {object} map[string]string "Parameter tidak valid"
 // @Failure 403 {object} map[string]string "Tidak memiliki izin mengakses resource"
 // @Failure 500 {object} map[string]string "Terjadi kesalahan server"
 //
@@ -328,27 +657,72 @@ func (s *AchievementService) List(c *fiber.Ctx) error {
 	offset := (page - 1) * limit
 
 	// ===== Filter & Sorting =====
-	sortBy := c.Query("sort", "created_at")
-	order := c.Query("order", "desc")
-	status := c.Query("status", "")
+	pageReq := model.PageRequest{
+		Limit:      limit,
+		Offset:     offset,
+		SortBy:     c.Query("sort", "created_at"),
+		Order:      c.Query("order", "desc"),
+		CountTotal: c.QueryBool("with_total"),
+	}
+
+	// mode cursor: ?pagination=cursor untuk halaman pertama, lalu ?cursor=<next_cursor>.
+	// Cursor membawa sort & order-nya sendiri.
+	if cursor := c.Query("cursor"); cursor != "" || c.Query("pagination") == "cursor" {
+		pageReq.Cursor = true
+		if cursor != "" {
+			after, err := decodeListCursor(cursor)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{"message": "cursor tidak valid"})
+			}
+			pageReq.After = after
+			pageReq.SortBy = after.Sort
+			pageReq.Order = after.Order
+		}
+	}
+
+	filter, search, err := parseListFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+	}
+
+	fields, err := parseFieldsQuery(c.Query("fields"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+	}
+
+	// filter dokumen Mongo dijawab dulu, hasilnya membatasi query Postgres
+	// sehingga pagination dan scoping role tetap di satu tempat
+	if !search.IsEmpty() {
+		filter.MongoIDs, err = s.MongoRepo.FindIDs(search, listFilterMaxMatches)
+		if err == repository.ErrFilterTooBroad {
+			return c.Status(400).JSON(fiber.Map{
+				"message": fmt.Sprintf("filter cocok dengan lebih dari %d prestasi, persempit pencarian", listFilterMaxMatches),
+			})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "failed to search achievements"})
+		}
+	}
 
 	var (
 		refs  []model.AchievementReference
 		total int
-		err   error
 	)
 
 	// ===== Role-based access =====
 	if ok, _ := s.PermissionService.HasPermission(roleID, "achievement:list:all"); ok {
 
 		refs, total, err = s.RefRepo.ListAll(
-			limit, offset, sortBy, order, status,
+			pageReq, filter, orgScope(c),
 		)
 
 	} else if ok, _ := s.PermissionService.HasPermission(roleID, "achievement:list:advisor"); ok {
 
+		// dosen wali sudah dibatasi ke bimbingannya sendiri
+		filter.AdvisorID = ""
+
 		refs, total, err = s.RefRepo.ListByLecturer(
-			userID, limit, offset, sortBy, order, status,
+			userID, pageReq, filter,
 		)
 
 	} else if ok, _ := s.PermissionService.HasPermission(roleID, "achievement:list:self"); ok {
@@ -360,8 +734,11 @@ func (s *AchievementService) List(c *fiber.Ctx) error {
 			})
 		}
 
+		filter.StudentID = ""
+		filter.AdvisorID = ""
+
 		refs, total, err = s.RefRepo.ListByStudent(
-			studentID, limit, offset, sortBy, order, status,
+			studentID, pageReq, filter,
 		)
 
 	} else {
@@ -370,20 +747,81 @@ func (s *AchievementService) List(c *fiber.Ctx) error {
 		})
 	}
 
+	if err == repository.ErrInvalidCursor {
+		return c.Status(400).JSON(fiber.Map{"message": "cursor tidak valid"})
+	}
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	// repository mengembalikan satu baris lebih di mode cursor
+	hasMore := pageReq.Cursor && len(refs) > limit
+	if hasMore {
+		refs = refs[:limit]
+	}
+
 	// ===== Build response data =====
+	mongoIDs := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		mongoIDs = append(mongoIDs, ref.MongoAchievementID)
+	}
+
+	docs, err := s.MongoRepo.FindByIDs(mongoIDs, fields)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "failed to load achievements"})
+	}
+
+	refIDs := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		refIDs = append(refIDs, ref.ID)
+	}
+	unread, err := s.CommentRepo.UnreadCounts(userID, refIDs, s.canSeeInternalComments(roleID))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "failed to count unread comments"})
+	}
+
+	// urutan mengikuti halaman dari Postgres
 	var results []fiber.Map
 	for _, ref := range refs {
-		mongoData, _ := s.MongoRepo.FindByID(ref.MongoAchievementID)
+		mongoData, found := docs[ref.MongoAchievementID]
 
-		results = append(results, fiber.Map{
+		item := fiber.Map{
 			"id":        ref.ID,
 			"status":    ref.Status,
 			"studentId": ref.StudentID,
 			"mongo":     mongoData,
+			// komentar dari orang lain yang belum dibaca user ini
+			"unreadComments": unread[ref.ID],
+		}
+		if !found {
+			// dokumen belum dibuat (outbox) atau hilang, lihat rekonsiliasi
+			item["mongoMissing"] = true
+		}
+		if ref.StudentName != "" {
+			item["studentName"] = ref.StudentName
+			item["submittedAt"] = ref.SubmittedAt
+		}
+
+		results = append(results, item)
+	}
+
+	if pageReq.Cursor {
+		meta := fiber.Map{
+			"limit":       limit,
+			"has_more":    hasMore,
+			"next_cursor": nil,
+		}
+		if hasMore {
+			meta["next_cursor"] = encodeListCursor(pageReq, refs[len(refs)-1])
+		}
+		if pageReq.CountTotal {
+			meta["total"] = total
+		}
+
+		return c.JSON(fiber.Map{
+			"status": "success",
+			"meta":   meta,
+			"data":   results,
 		})
 	}
 
@@ -399,15 +837,179 @@ func (s *AchievementService) List(c *fiber.Ctx) error {
 	})
 }
 
+// encodeListCursor membuat cursor opak (base64url JSON) dari baris terakhir halaman
+func encodeListCursor(page model.PageRequest, last model.AchievementReference) string {
+	sortBy, order := repository.SanitizeSort(page.SortBy, page.Order)
+	data, _ := json.Marshal(model.ListCursor{
+		Sort:  sortBy,
+		Order: order,
+		Value: last.SortKey,
+		ID:    last.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListCursor(value string) (*model.ListCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor model.ListCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID == "" {
+		return nil, fmt.Errorf("cursor tanpa id")
+	}
+
+	return &cursor, nil
+}
+
+// batas jumlah _id hasil filter Mongo yang diteruskan ke Postgres sebagai ANY($n)
+const listFilterMaxMatches = 5000
+
+// parseListFilter membaca query string filter list. Filter kolom Postgres dan
+// filter dokumen Mongo dipisah karena dijawab oleh store yang berbeda.
+func parseListFilter(c *fiber.Ctx) (model.AchievementListFilter, model.AchievementSearchFilter, error) {
+	filter := model.AchievementListFilter{
+		Status:    c.Query("status"),
+		StudentID: c.Query("student_id"),
+		AdvisorID: c.Query("advisor_id"),
+	}
+	search := model.AchievementSearchFilter{
+		AchievementType:  c.Query("type"),
+		CompetitionLevel: c.Query("competition_level"),
+		Query:            strings.TrimSpace(c.Query("q")),
+	}
+
+	if filter.StudentID != "" && !isUUID(filter.StudentID) {
+		return filter, search, fmt.Errorf("student_id tidak valid")
+	}
+	if filter.AdvisorID != "" && !isUUID(filter.AdvisorID) {
+		return filter, search, fmt.Errorf("advisor_id tidak valid")
+	}
+
+	// prestasi yang dihapus tidak pernah ditampilkan, jadi bukan nilai filter yang sah
+	if _, ok := model.AchievementStatusMap[filter.Status]; filter.Status != "" && (!ok || filter.Status == model.StatusDeleted) {
+		return filter, search, fmt.Errorf("status tidak valid")
+	}
+
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			search.Tags = append(search.Tags, tag)
+		}
+	}
+
+	dates := []struct {
+		param  string
+		alias  string
+		target **time.Time
+		to     bool
+	}{
+		{"created_from", "", &filter.CreatedFrom, false},
+		{"created_to", "", &filter.CreatedTo, true},
+		{"submitted_from", "from", &filter.SubmittedFrom, false},
+		{"submitted_to", "to", &filter.SubmittedTo, true},
+		{"verified_from", "", &filter.VerifiedFrom, false},
+		{"verified_to", "", &filter.VerifiedTo, true},
+	}
+	for _, d := range dates {
+		param, value := d.param, c.Query(d.param)
+		if value == "" && d.alias != "" {
+			param, value = d.alias, c.Query(d.alias)
+		}
+
+		t, err := parseDateQuery(value, d.to)
+		if err != nil {
+			return filter, search, fmt.Errorf("format %s harus YYYY-MM-DD", param)
+		}
+		*d.target = t
+	}
+
+	for param, target := range map[string]**int{
+		"points_min": &filter.PointsMin,
+		"points_max": &filter.PointsMax,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, search, fmt.Errorf("%s harus berupa angka", param)
+		}
+		*target = &n
+	}
+
+	return filter, search, nil
+}
+
+// field dokumen achievements yang boleh diminta lewat ?fields=
+var listProjectableFields = map[string]bool{
+	"studentId":       true,
+	"achievementType": true,
+	"title":           true,
+	"description":     true,
+	"details":         true,
+	"attachments":     true,
+	"tags":            true,
+	"points":          true,
+	"createdAt":       true,
+	"updatedAt":       true,
+}
+
+// parseFieldsQuery membaca ?fields=title,points menjadi daftar proyeksi Mongo
+func parseFieldsQuery(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	fields := []string{}
+	for _, f := range strings.Split(value, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !listProjectableFields[f] {
+			return nil, fmt.Errorf("field %q tidak dikenal", f)
+		}
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// parseDateQuery membaca tanggal YYYY-MM-DD dari query string.
+// endOfDay=true menggeser ke awal hari berikutnya supaya batas atas inklusif.
+func parseDateQuery(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return &t, nil
+}
+
 // Detail godoc
 // @Summary Achievement detail
-// @Description Detail prestasi (owner, advisor, atau admin)
+// @Description Detail prestasi (owner, advisor, atau admin).
+// Header ETag berisi versi dokumen untuk If-Match pada PUT dan upload attachment.
 // @Tags Achievement
 // @Produce json
 // @Param id path string true "Achievement ID"
 // @Success 200 {object} model.AchievementDetailResponse
 // @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/achievements/{id} [get]
 func (s *AchievementService) Detail(c *fiber.Ctx) error {
@@ -421,14 +1023,24 @@ func (s *AchievementService) Detail(c *fiber.Ctx) error {
 			"message": "Achievement tidak ditemukan",
 		})
 	}
-	
-	if studentID, err := s.RefRepo.GetStudentIDByUser(userID); err == nil {
-		if studentID != ref.StudentID {
-			// bukan owner → cek advisor
-			isAdvisor, _ := s.RefRepo.IsAdvisorOf(userID, ref.StudentID)
-			if !isAdvisor {
-				return c.Status(403).JSON(fiber.Map{
-					"error": "forbidden",
+
+	if ok, err := s.canViewAchievement(c, ref); !ok {
+		return viewAccessError(c, err)
+	}
+
+	// dosen wali membuka pengajuan → pemeriksaan dianggap sudah dimulai
+	// (menutup kesempatan withdraw), jadi kegagalannya tidak boleh diabaikan
+	if ref.Status == model.StatusSubmitted {
+		isAdvisor, err := s.RefRepo.IsAdvisorOf(userID, ref.StudentID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"message": "Gagal memeriksa dosen wali",
+			})
+		}
+		if isAdvisor {
+			if err := s.RefRepo.MarkReviewStarted(ref.ID); err != nil {
+				return c.Status(500).JSON(fiber.Map{
+					"message": "Gagal menandai pemeriksaan dimulai",
 				})
 			}
 		}
@@ -441,71 +1053,51 @@ func (s *AchievementService) Detail(c *fiber.Ctx) error {
 		})
 	}
 
+	c.Set(fiber.HeaderETag, achievementETag(docVersion(mongoData)))
 	return c.JSON(fiber.Map{
 		"status": "success",
 		"data": fiber.Map{
-			"id":                 ref.ID,
-			"status":             ref.Status,
-			"created_at":         ref.CreatedAt,
-			"achievement_detail": mongoData,
+			"id":                     ref.ID,
+			"status":                 ref.Status,
+			"created_at":             ref.CreatedAt,
+			"suggested_points":       ref.SuggestedPoints,
+			"awarded_points":         ref.AwardedPoints,
+			"points_override_reason": ref.PointsOverride,
+			"achievement_detail":     mongoData,
 		},
 	})
 }
 
 // Update godoc
 // @Summary Update achievement draft
-// @Description Update draft prestasi milik mahasiswa
+// @Description Update draft prestasi milik mahasiswa, atau prestasi yang ditolak / diminta revisi sebelum diajukan ulang.
+// Field yang tidak dikirim tidak berubah; details yang dikirim menggantikan details lama.
+// If-Match wajib berisi ETag terakhir; 412 kalau dokumen sudah diubah di tempat lain.
 // @Tags Achievement
 // @Accept json
 // @Produce json
 // @Param id path string true "Achievement ID"
+// @Param If-Match header string true "ETag dari GET /achievements/:id"
 // @Param request body model.UpdateAchievementRequest true "Update payload"
 // @Success 200 {object} model.UpdateAchievementResponse
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 422 {object} model.DetailsValidationResponse
 // @Failure 403 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
+// @Failure 428 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/achievements/{id} [put]
 func (s *AchievementService) Update(c *fiber.Ctx) error {
-	id := c.Params("id")
-	userID := c.Locals("user_id").(string)
-
-	studentID, err := s.RefRepo.GetStudentIDByUser(userID)
-	if err != nil {
-		return c.Status(403).JSON(fiber.Map{
-			"message": "Data mahasiswa tidak ditemukan",
-		})
-	}
-
-	var payload map[string]interface{}
-	if err := c.BodyParser(&payload); err != nil {
+	var req model.UpdateAchievementRequest
+	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{
 			"message": "Payload invalid",
 		})
 	}
 
-	ref, err := s.RefRepo.GetDraftByOwner(id, studentID)
-	if err != nil {
-		return c.Status(403).JSON(fiber.Map{
-			"message": "Draft tidak ditemukan atau bukan milik Anda",
-		})
-	}
-
-	err = s.MongoRepo.UpdateByID(ref.MongoAchievementID, payload)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal update data",
-		})
-	}
-
-	return c.JSON(fiber.Map{
-		"status":  "success",
-		"message": "Draft berhasil diupdate",
-		"data": fiber.Map{
-			"id":       id,
-			"mongo_id": ref.MongoAchievementID,
-		},
-	})
+	// field di luar UpdateAchievementRequest (studentId, points, dst.) diabaikan
+	return s.applyAchievementPatch(c, updateRequestPatch(req), true)
 }
 
 // Delete godoc
@@ -538,13 +1130,13 @@ func (s *AchievementService) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	if ref.Status != "draft" {
+	if !model.CanTransition(model.ActionDelete, ref.Status) {
 		return c.Status(409).JSON(fiber.Map{
 			"message": "Hanya draft yang dapat dihapus",
 		})
 	}
 
-	if err := s.RefRepo.DeleteDraft(id, studentID); err != nil {
+	if err := s.RefRepo.DeleteDraft(id, studentID, userID); err != nil {
 		return c.Status(409).JSON(fiber.Map{
 			"message": "Draft tidak dapat dihapus (mungkin sudah diproses)",
 		})
@@ -561,15 +1153,18 @@ func (s *AchievementService) Delete(c *fiber.Ctx) error {
 
 // UploadAttachment godoc
 // @Summary Upload achievement attachment
-// @Description Upload file pendukung prestasi
+// @Description Upload file pendukung prestasi. If-Match wajib berisi ETag terakhir.
 // @Tags Achievement
 // @Accept multipart/form-data
 // @Produce json
 // @Param id path string true "Achievement ID"
+// @Param If-Match header string true "ETag dari GET /achievements/:id"
 // @Param file formData file true "Attachment file"
 // @Success 200 {object} model.UploadAttachmentResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 403 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
+// @Failure 428 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/achievements/{id}/attachments [post]
@@ -594,12 +1189,18 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 	}
 
 	// 🔒 Kunci kalau sudah diverifikasi
-	if ref.Status == "verified" {
+	if ref.Status == model.StatusVerified {
 		return c.Status(403).JSON(fiber.Map{
 			"message": "Prestasi yang sudah diverifikasi tidak bisa diubah",
 		})
 	}
 
+	// dicek sebelum file disimpan supaya request tanpa If-Match tidak meninggalkan file
+	version, err := parseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return preconditionError(c, err)
+	}
+
 	file, err := c.FormFile("file")
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{
@@ -608,17 +1209,8 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 	}
 
 	// ✅ Path lebih aman pakai studentID asli
-	uploadDir := fmt.Sprintf("uploads/%s", studentID)
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal membuat folder upload",
-		})
-	}
-
-	fileName := fmt.Sprintf("%s_%s", uuid.NewString(), file.Filename)
-	uploadPath := fmt.Sprintf("%s/%s", uploadDir, fileName)
-
-	if err := c.SaveFile(file, uploadPath); err != nil {
+	uploadPath, err := utils.SaveUpload(c, file, studentID)
+	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"message": "Gagal menyimpan file",
 		})
@@ -631,13 +1223,18 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 		UploadedAt: time.Now(),
 	}
 
-	err = s.MongoRepo.AddAttachment(ref.MongoAchievementID, attachment)
+	newVersion, err := s.MongoRepo.AddAttachmentIfVersion(ref.MongoAchievementID, version, attachment)
 	if err != nil {
+		_ = os.Remove(uploadPath)
+		if err == repository.ErrVersionConflict {
+			return versionMismatch(c)
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"message": "Gagal menyimpan attachment ke MongoDB",
 		})
 	}
 
+	c.Set(fiber.HeaderETag, achievementETag(newVersion))
 	return c.JSON(fiber.Map{
 		"status":  "success",
 		"message": "Attachment berhasil diupload",
@@ -659,21 +1256,14 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 // @Router /api/v1/achievements/{id}/history [get]
 func (s *AchievementService) History(c *fiber.Ctx) error {
 	id := c.Params("id")
-	userID := c.Locals("user_id").(string)
 
 	ref, err := s.RefRepo.GetByID(id)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{"message": "Achievement tidak ditemukan"})
 	}
 
-	studentID, err := s.RefRepo.GetStudentIDByUser(userID)
-	if err == nil {
-		isOwner := studentID == ref.StudentID
-		isAdvisor, _ := s.RefRepo.IsAdvisorOf(userID, ref.StudentID)
-
-		if !isOwner && !isAdvisor {
-			return c.Status(403).JSON(fiber.Map{"error": "forbidden"})
-		}
+	if ok, err := s.canViewAchievement(c, ref); !ok {
+		return viewAccessError(c, err)
 	}
 
 	history, err := s.RefRepo.GetHistory(id)
@@ -681,8 +1271,8 @@ func (s *AchievementService) History(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"message": "Gagal mengambil history"})
 	}
 
-	return c.JSON(fiber.Map{
-		"status":  "success",
-		"history": history,
+	return c.JSON(model.AchievementHistoryResponse{
+		Status:  "success",
+		History: history,
 	})
 }
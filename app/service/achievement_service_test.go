@@ -0,0 +1,441 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAchievementApp(service *AchievementService, userID string) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", userID)
+		return c.Next()
+	})
+	app.Post("/achievements/:id/submit", service.Submit)
+	app.Get("/achievements/:id/history", service.History)
+	app.Post("/achievements/:id/withdraw", service.Withdraw)
+	app.Put("/achievements/:id", service.Update)
+	app.Patch("/achievements/:id", service.Patch)
+	return app
+}
+
+func TestParseFieldsQuery(t *testing.T) {
+	fields, err := parseFieldsQuery("")
+	assert.NoError(t, err)
+	assert.Nil(t, fields)
+
+	fields, err = parseFieldsQuery("title, points,,achievementType")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"title", "points", "achievementType"}, fields)
+
+	_, err = parseFieldsQuery("title,$where")
+	assert.Error(t, err)
+}
+
+func TestParseListFilter(t *testing.T) {
+	var (
+		filter model.AchievementListFilter
+		search model.AchievementSearchFilter
+		err    error
+	)
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		filter, search, err = parseListFilter(c)
+		return nil
+	})
+
+	_, _ = app.Test(httptest.NewRequest("GET",
+		"/?status=verified&type=competition&tags=ai,%20ml&q=%20robot%20&from=2026-03-01&verified_to=2026-03-31&points_min=10", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusVerified, filter.Status)
+	assert.Equal(t, "competition", search.AchievementType)
+	assert.Equal(t, []string{"ai", "ml"}, search.Tags)
+	assert.Equal(t, "robot", search.Query)
+	assert.Equal(t, "2026-03-01", filter.SubmittedFrom.Format("2006-01-02"))
+	// batas atas inklusif → awal hari berikutnya
+	assert.Equal(t, "2026-04-01", filter.VerifiedTo.Format("2006-01-02"))
+	assert.Equal(t, 10, *filter.PointsMin)
+	assert.Nil(t, filter.PointsMax)
+	assert.Nil(t, filter.MongoIDs)
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/?created_from=01-03-2026", nil))
+	assert.EqualError(t, err, "format created_from harus YYYY-MM-DD")
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/?points_max=banyak", nil))
+	assert.EqualError(t, err, "points_max harus berupa angka")
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/?status=deleted", nil))
+	assert.EqualError(t, err, "status tidak valid")
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/?student_id=s1", nil))
+	assert.EqualError(t, err, "student_id tidak valid")
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/?advisor_id=l1", nil))
+	assert.EqualError(t, err, "advisor_id tidak valid")
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/", nil))
+	assert.NoError(t, err)
+	assert.True(t, search.IsEmpty())
+}
+
+func TestParseDateQuery(t *testing.T) {
+	from, err := parseDateQuery("2026-03-01", false)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local), *from)
+
+	// batas atas inklusif → awal hari berikutnya
+	to, err := parseDateQuery("2026-03-31", true)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local), *to)
+
+	empty, err := parseDateQuery("", true)
+	assert.NoError(t, err)
+	assert.Nil(t, empty)
+
+	_, err = parseDateQuery("31-03-2026", false)
+	assert.Error(t, err)
+}
+
+var competitionType = model.AchievementType{
+	Code: "competition",
+	Schema: model.DetailSchema{Fields: []model.AchievementField{
+		{Name: "competitionName", Type: model.FieldString, Required: true},
+		{Name: "competitionLevel", Type: model.FieldEnum, Required: true,
+			Enum: []string{"international", "national", "regional", "local"}},
+		{Name: "rank", Type: model.FieldNumber, Required: true},
+		{Name: "eventDate", Type: model.FieldDate},
+	}},
+}
+
+func TestValidateAchievementDetails_Competition(t *testing.T) {
+	details := map[string]interface{}{
+		"competitionName":  "Gemastik",
+		"competitionLevel": "galaxy",
+		"eventDate":        "12/05/2026",
+	}
+
+	// draft: field wajib yang kosong belum dicek, tapi enum & format tetap dicek
+	errs := validateAchievementDetails(competitionType, details, false)
+	assert.Equal(t, []model.DetailFieldError{
+		{Field: "competitionLevel", Message: "harus salah satu dari: international, national, regional, local"},
+		{Field: "eventDate", Message: "format tanggal harus YYYY-MM-DD"},
+	}, errs)
+
+	details["competitionLevel"] = "national"
+	details["eventDate"] = "2026-05-12"
+	errs = validateAchievementDetails(competitionType, details, true)
+	assert.Equal(t, []model.DetailFieldError{{Field: "rank", Message: "wajib diisi"}}, errs)
+
+	details["rank"] = float64(1)
+	assert.Empty(t, validateAchievementDetails(competitionType, details, true))
+}
+
+func TestValidateAchievementDetails_UnknownAndCrossField(t *testing.T) {
+	organization := model.AchievementType{
+		Code: "organization",
+		Schema: model.DetailSchema{Fields: []model.AchievementField{
+			{Name: "organizationName", Type: model.FieldString, Required: true},
+			{Name: "periodStart", Type: model.FieldDate, Required: true},
+			{Name: "periodEnd", Type: model.FieldDate},
+		}},
+	}
+
+	errs := validateAchievementDetails(organization, map[string]interface{}{
+		"organizationName": "BEM",
+		"periodStart":      "2026-01-01",
+		"periodEnd":        "2025-12-31",
+		"hobby":            "catur",
+	}, true)
+
+	assert.Equal(t, []model.DetailFieldError{
+		{Field: "hobby", Message: "field tidak dikenal untuk tipe organization"},
+		{Field: "periodEnd", Message: "tidak boleh sebelum periodStart"},
+	}, errs)
+
+	// tipe dengan allow_extra boleh menyimpan field bebas
+	other := model.AchievementType{Code: "other", Schema: model.DetailSchema{AllowExtra: true}}
+	assert.Empty(t, validateAchievementDetails(other, map[string]interface{}{"hobby": "catur"}, true))
+}
+
+func TestValidateAchievementType(t *testing.T) {
+	req := model.AchievementTypeRequest{
+		Code:   "competition",
+		Name:   "Kompetisi",
+		Schema: competitionType.Schema,
+		PointRules: []model.PointRule{
+			{Field: "competitionLevel", Values: map[string]float64{"international": 100, "national": 75}},
+		},
+	}
+	assert.Equal(t, "", validateAchievementType(req))
+
+	req.PointRules[0].Field = "competitionName"
+	assert.Equal(t, "point_rules hanya boleh memakai field enum atau number di schema", validateAchievementType(req))
+
+	req.PointRules[0] = model.PointRule{Field: "rank", Op: model.PointOpMultiply, Values: map[string]float64{"juara 1": 1}}
+	assert.Equal(t, "point_rules rank: nilai juara 1 harus angka", validateAchievementType(req))
+
+	req.PointRules = nil
+	req.Code = "Kompetisi Nasional"
+	assert.Equal(t, "code harus huruf kecil/angka/underscore, 2-30 karakter", validateAchievementType(req))
+}
+
+func TestCalculatePoints(t *testing.T) {
+	rankDefault := 0.4
+	competition := competitionType
+	competition.BasePoints = 10
+	competition.PointRules = []model.PointRule{
+		{Field: "competitionLevel", Op: model.PointOpAdd, Values: map[string]float64{"international": 100, "national": 75}},
+		{Field: "rank", Op: model.PointOpMultiply, Values: map[string]float64{"1": 1, "2": 0.8}, Default: &rankDefault},
+	}
+
+	// (10 + 75) × 0.8
+	assert.Equal(t, 68, calculatePoints(competition, map[string]interface{}{
+		"competitionLevel": "national", "rank": float64(2),
+	}))
+
+	// rank dari MongoDB berupa int32, di luar daftar → default 0.4
+	assert.Equal(t, 44, calculatePoints(competition, map[string]interface{}{
+		"competitionLevel": "international", "rank": int32(5),
+	}))
+
+	// level tidak dikenal: add default 0, rank kosong: multiply default
+	assert.Equal(t, 4, calculatePoints(competition, map[string]interface{}{}))
+}
+
+func TestResolveAwardedPoints(t *testing.T) {
+	suggested := 68
+	custom := 80
+
+	points, reason, msg := resolveAwardedPoints(&suggested, model.VerifyAchievementRequest{})
+	assert.Equal(t, 68, points)
+	assert.Nil(t, reason)
+	assert.Empty(t, msg)
+
+	_, _, msg = resolveAwardedPoints(&suggested, model.VerifyAchievementRequest{Points: &custom})
+	assert.Equal(t, "override_reason wajib diisi jika poin berbeda dari usulan", msg)
+
+	points, reason, msg = resolveAwardedPoints(&suggested, model.VerifyAchievementRequest{
+		Points: &custom, OverrideReason: " juara umum ",
+	})
+	assert.Equal(t, 80, points)
+	assert.Equal(t, "juara umum", *reason)
+	assert.Empty(t, msg)
+
+	_, _, msg = resolveAwardedPoints(nil, model.VerifyAchievementRequest{})
+	assert.NotEmpty(t, msg)
+}
+
+func TestSubmit_ResubmissionLimitReached(t *testing.T) {
+	t.Setenv("ACHIEVEMENT_MAX_RESUBMISSIONS", "2")
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id FROM students WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("s1"))
+
+	// sudah ditolak 3 kali, jatah 2 kali pengajuan ulang habis
+	mock.ExpectQuery(`FROM achievement_references ar[\s\S]+ar.status = ANY\(\$4\)`).
+		WithArgs("a1", "s1", model.StatusRejected, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "mongo_achievement_id", "status", "count"}).
+			AddRow("a1", "m1", "rejected", 3))
+
+	service := NewAchievementService(nil, repository.NewAchievementReferenceRepository(db), nil, nil, nil, nil, nil, nil, nil)
+	app := newAchievementApp(service, "u1")
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/achievements/a1/submit", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHistory_ReadsStatusHistoryTable(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	created := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT id, student_id, mongo_achievement_id, status, created_at`).
+		WithArgs("a1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "created_at",
+			"suggested_points", "awarded_points", "points_override_reason"}).
+			AddRow("a1", "s1", "m1", "submitted", created, 68, nil, nil))
+
+	mock.ExpectQuery(`SELECT id FROM students WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("s1"))
+
+	mock.ExpectQuery(`FROM achievement_status_history[\s\S]+ORDER BY changed_at`).
+		WithArgs("a1").
+		WillReturnRows(sqlmock.NewRows([]string{"from_status", "to_status", "changed_at", "actor_id", "note"}).
+			AddRow("", "draft", created, "u1", "").
+			AddRow("draft", "submitted", created.Add(time.Hour), "u1", "").
+			AddRow("submitted", "rejected", created.Add(48*time.Hour), "lect-1", "bukti kurang").
+			AddRow("rejected", "submitted", created.Add(72*time.Hour), "u1", "").
+			AddRow("submitted", "rejected", created.Add(96*time.Hour), "lect-1", "masih kurang"))
+
+	service := NewAchievementService(nil, repository.NewAchievementReferenceRepository(db), nil, nil, nil, nil, nil, nil, nil)
+	app := newAchievementApp(service, "u1")
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/achievements/a1/history", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body model.AchievementHistoryResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	statuses := []string{}
+	for _, h := range body.History {
+		statuses = append(statuses, h.Status)
+	}
+	// penolakan kedua tidak menimpa penolakan pertama
+	assert.Equal(t, []string{"draft", "submitted", "rejected", "submitted", "rejected"}, statuses)
+	assert.Equal(t, "bukti kurang", body.History[2].Note)
+	assert.Equal(t, "lect-1", body.History[2].UpdatedBy)
+	assert.Equal(t, "rejected", body.History[3].FromStatus)
+	assert.Equal(t, "masih kurang", body.History[4].Note)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAchievementTransitions(t *testing.T) {
+	assert.True(t, model.CanTransition(model.ActionSubmit, model.StatusRevisionRequested))
+	assert.True(t, model.CanTransition(model.ActionWithdraw, model.StatusSubmitted))
+	assert.False(t, model.CanTransition(model.ActionVerify, model.StatusDraft))
+	assert.False(t, model.CanTransition(model.ActionDelete, model.StatusSubmitted))
+	assert.True(t, model.CanTransition(model.ActionQuarantine, model.StatusSubmitted))
+	assert.False(t, model.CanTransition(model.ActionQuarantine, model.StatusVerified))
+	assert.False(t, model.CanTransition("publish", model.StatusDraft))
+
+	// semua tujuan transisi harus status yang dikenal
+	for action, transition := range model.AchievementTransitions {
+		_, ok := model.AchievementStatusMap[transition.To]
+		assert.True(t, ok, action)
+	}
+}
+
+func expectOwnedAchievement(mock sqlmock.Sqlmock, status string, reviewStarted interface{}) {
+	mock.ExpectQuery(`SELECT id FROM students WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("s1"))
+
+	mock.ExpectQuery(`FROM achievement_references\s+WHERE id = \$1 AND student_id = \$2`).
+		WithArgs("a1", "s1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "created_at", "review_started_at"}).
+			AddRow("a1", "s1", "m1", status, time.Now(), reviewStarted))
+}
+
+func TestWithdraw_ReviewAlreadyStarted(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectOwnedAchievement(mock, model.StatusSubmitted, time.Now())
+
+	service := NewAchievementService(nil, repository.NewAchievementReferenceRepository(db), nil, nil, nil, nil, nil, nil, nil)
+	app := newAchievementApp(service, "u1")
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/achievements/a1/withdraw", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithdraw_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectOwnedAchievement(mock, model.StatusSubmitted, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM achievement_references WHERE id = \$1 FOR UPDATE`).
+		WithArgs("a1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StatusSubmitted))
+	mock.ExpectExec(`UPDATE achievement_references[\s\S]+review_started_at IS NULL`).
+		WithArgs("a1", "s1", model.StatusDraft, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE achievement_submissions`).
+		WithArgs("a1", "withdrawn", "u1", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO achievement_status_history`).
+		WithArgs("a1", model.StatusSubmitted, model.StatusDraft, "u1", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT l.user_id`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "full_name"}).AddRow("lect-user", "Budi"))
+	mock.ExpectExec(`INSERT INTO notifications`).
+		WithArgs("lect-user", model.NotificationAchievementWithdrawn, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	service := NewAchievementService(nil, repository.NewAchievementReferenceRepository(db), nil, nil, nil, nil, nil, nil, nil)
+	app := newAchievementApp(service, "u1")
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/achievements/a1/withdraw", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListCursor_RoundTrip(t *testing.T) {
+	value := "2026-03-01 08:00:00.123456"
+	encoded := encodeListCursor(
+		model.PageRequest{SortBy: "submitted_at", Order: "ASC"},
+		model.AchievementReference{ID: "a9", SortKey: &value},
+	)
+
+	cursor, err := decodeListCursor(encoded)
+	assert.NoError(t, err)
+	// order tidak valid dinormalisasi sama seperti query list
+	assert.Equal(t, model.ListCursor{Sort: "submitted_at", Order: "desc", Value: &value, ID: "a9"}, *cursor)
+
+	_, err = decodeListCursor("bukan-cursor")
+	assert.Error(t, err)
+}
+
+func TestListByStudent_CursorPage(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	value := "2026-03-01 08:00:00"
+	page := model.PageRequest{
+		Limit:  2,
+		SortBy: "created_at",
+		Order:  "desc",
+		Cursor: true,
+		After:  &model.ListCursor{Sort: "created_at", Order: "desc", Value: &value, ID: "a5"},
+	}
+
+	// tanpa COUNT(*), kondisi keyset, dan LIMIT+1
+	mock.ExpectQuery(`FROM achievement_references ar WHERE ar.student_id = \$1 AND ar.status = \$2 AND ` +
+		`\(ar.created_at < \$3 OR \(ar.created_at = \$3 AND ar.id < \$4\) OR ar.created_at IS NULL\)\s+` +
+		`ORDER BY ar.created_at desc NULLS LAST, ar.id desc LIMIT \$5`).
+		WithArgs("s1", model.StatusDraft, value, "a5", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id", "status", "mongo_achievement_id", "created_at"}).
+			AddRow("a4", "s1", "draft", "m4", "2026-02-28 10:00:00").
+			AddRow("a3", "s1", "draft", "m3", "2026-02-27 10:00:00").
+			AddRow("a2", "s1", "draft", "m2", "2026-02-26 10:00:00"))
+
+	repo := repository.NewAchievementReferenceRepository(db)
+	refs, total, err := repo.ListByStudent("s1", page, model.AchievementListFilter{Status: model.StatusDraft})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Len(t, refs, 3)
+	assert.Equal(t, "2026-02-27 10:00:00", *refs[1].SortKey)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// cursor dari sort lain ditolak
+	page.SortBy = "status"
+	_, _, err = repo.ListByStudent("s1", page, model.AchievementListFilter{})
+	assert.Equal(t, repository.ErrInvalidCursor, err)
+}
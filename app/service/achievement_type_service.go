@@ -0,0 +1,495 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AchievementTypeService struct {
+	Repo *repository.AchievementTypeRepository
+}
+
+func NewAchievementTypeService(repo *repository.AchievementTypeRepository) *AchievementTypeService {
+	return &AchievementTypeService{Repo: repo}
+}
+
+var achievementTypeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,29}$`)
+
+// validateAchievementType memeriksa definisi tipe dari admin sebelum disimpan
+func validateAchievementType(req model.AchievementTypeRequest) string {
+	if !achievementTypeCodePattern.MatchString(req.Code) {
+		return "code harus huruf kecil/angka/underscore, 2-30 karakter"
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return "name wajib diisi"
+	}
+	if req.BasePoints < 0 {
+		return "base_points tidak boleh negatif"
+	}
+
+	fields := map[string]model.AchievementField{}
+	for _, field := range req.Schema.Fields {
+		if field.Name == "" {
+			return "nama field wajib diisi"
+		}
+		if _, dup := fields[field.Name]; dup {
+			return "field " + field.Name + " duplikat"
+		}
+		if !model.DetailFieldTypes[field.Type] {
+			return "tipe field " + field.Name + " tidak dikenal"
+		}
+		if field.Type == model.FieldEnum && len(field.Enum) == 0 {
+			return "field enum " + field.Name + " wajib memiliki pilihan"
+		}
+		fields[field.Name] = field
+	}
+
+	for _, rule := range req.PointRules {
+		if msg := validatePointRule(rule, fields); msg != "" {
+			return msg
+		}
+	}
+
+	return ""
+}
+
+func validatePointRule(rule model.PointRule, fields map[string]model.AchievementField) string {
+	if rule.Op != "" && rule.Op != model.PointOpAdd && rule.Op != model.PointOpMultiply {
+		return "op point_rules harus add atau multiply"
+	}
+
+	field, ok := fields[rule.Field]
+	if !ok || (field.Type != model.FieldEnum && field.Type != model.FieldNumber) {
+		return "point_rules hanya boleh memakai field enum atau number di schema"
+	}
+
+	for value, points := range rule.Values {
+		if field.Type == model.FieldEnum && !slices.Contains(field.Enum, value) {
+			return "point_rules " + rule.Field + ": nilai " + value + " tidak ada di enum"
+		}
+		if _, err := strconv.ParseFloat(value, 64); field.Type == model.FieldNumber && err != nil {
+			return "point_rules " + rule.Field + ": nilai " + value + " harus angka"
+		}
+		if points < 0 {
+			return "poin tidak boleh negatif"
+		}
+	}
+
+	if rule.Default != nil && *rule.Default < 0 {
+		return "poin tidak boleh negatif"
+	}
+
+	return ""
+}
+
+// calculatePoints menghitung poin usulan: mulai dari base_points lalu
+// setiap rule diterapkan berurutan sesuai op-nya
+func calculatePoints(achievementType model.AchievementType, details map[string]interface{}) int {
+	points := float64(achievementType.BasePoints)
+
+	for _, rule := range achievementType.PointRules {
+		value, ok := rule.Values[pointRuleKey(details[rule.Field])]
+		if !ok {
+			switch {
+			case rule.Default != nil:
+				value = *rule.Default
+			case rule.Op == model.PointOpMultiply:
+				value = 1
+			default:
+				value = 0
+			}
+		}
+
+		if rule.Op == model.PointOpMultiply {
+			points *= value
+		} else {
+			points += value
+		}
+	}
+
+	return int(math.Round(points))
+}
+
+// nilai details → key di PointRule.Values (angka 2.0 menjadi "2")
+func pointRuleKey(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	if num, ok := detailNumber(value); ok {
+		return strconv.FormatFloat(num, 'f', -1, 64)
+	}
+	return ""
+}
+
+// List godoc
+// @Summary List achievement types
+// @Description Katalog jenis prestasi beserta schema details dan aturan poin (termasuk yang nonaktif)
+// @Tags AchievementType
+// @Produce json
+// @Success 200 {object} model.AchievementTypeListResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievement-types [get]
+func (s *AchievementTypeService) List(c *fiber.Ctx) error {
+	data, err := s.Repo.GetAll(false)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil jenis prestasi"})
+	}
+
+	return c.JSON(model.AchievementTypeListResponse{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+// Schemas godoc
+// @Summary List achievement type schemas
+// @Description Jenis prestasi aktif beserta schema details untuk membangun form
+// @Tags Achievement
+// @Produce json
+// @Success 200 {object} model.AchievementTypeListResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/schemas [get]
+func (s *AchievementTypeService) Schemas(c *fiber.Ctx) error {
+	data, err := s.Repo.GetAll(true)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil jenis prestasi"})
+	}
+
+	return c.JSON(model.AchievementTypeListResponse{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+// Schema godoc
+// @Summary Get achievement type schema
+// @Description Schema details untuk satu jenis prestasi aktif
+// @Tags Achievement
+// @Produce json
+// @Param type path string true "Achievement type code"
+// @Success 200 {object} model.AchievementTypeResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/schemas/{type} [get]
+func (s *AchievementTypeService) Schema(c *fiber.Ctx) error {
+	t, err := s.Repo.GetByCode(c.Params("type"))
+	if err != nil || !t.IsActive {
+		return c.Status(404).JSON(fiber.Map{"error": "achievement_type tidak dikenal"})
+	}
+
+	return c.JSON(model.AchievementTypeResponse{
+		Status: "success",
+		Data:   *t,
+	})
+}
+
+// Create godoc
+// @Summary Tambah jenis prestasi
+// @Tags AchievementType
+// @Accept json
+// @Produce json
+// @Param request body model.AchievementTypeRequest true "Definisi jenis prestasi"
+// @Success 201 {object} model.AchievementTypeResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievement-types [post]
+func (s *AchievementTypeService) Create(c *fiber.Ctx) error {
+	var req model.AchievementTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	if msg := validateAchievementType(req); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	if _, err := s.Repo.GetByCode(req.Code); err == nil {
+		return c.Status(409).JSON(fiber.Map{"error": "code sudah dipakai"})
+	}
+
+	t := model.AchievementType{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		Schema:      req.Schema,
+		BasePoints:  req.BasePoints,
+		PointRules:  req.PointRules,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+
+	if err := s.Repo.Create(&t); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal menyimpan jenis prestasi"})
+	}
+
+	return c.Status(201).JSON(model.AchievementTypeResponse{
+		Status: "success",
+		Data:   t,
+	})
+}
+
+// Update godoc
+// @Summary Update jenis prestasi
+// @Description Code tidak bisa diubah karena tersimpan di dokumen prestasi
+// @Tags AchievementType
+// @Accept json
+// @Produce json
+// @Param code path string true "Achievement type code"
+// @Param request body model.AchievementTypeRequest true "Definisi jenis prestasi"
+// @Success 200 {object} model.AchievementTypeResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievement-types/{code} [put]
+func (s *AchievementTypeService) Update(c *fiber.Ctx) error {
+	code := c.Params("code")
+
+	t, err := s.Repo.GetByCode(code)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "jenis prestasi tidak ditemukan"})
+	}
+
+	var req model.AchievementTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	req.Code = code
+	if msg := validateAchievementType(req); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	t.Name = req.Name
+	t.Description = req.Description
+	t.Schema = req.Schema
+	t.BasePoints = req.BasePoints
+	t.PointRules = req.PointRules
+	if req.IsActive != nil {
+		t.IsActive = *req.IsActive
+	}
+
+	if err := s.Repo.Update(t); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal update jenis prestasi"})
+	}
+
+	updated, err := s.Repo.GetByCode(code)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "jenis prestasi tidak ditemukan"})
+	}
+
+	return c.JSON(model.AchievementTypeResponse{
+		Status: "success",
+		Data:   *updated,
+	})
+}
+
+// PreviewPoints godoc
+// @Summary Preview poin jenis prestasi
+// @Description Menghitung poin usulan dari details contoh memakai aturan poin yang tersimpan, untuk menguji konfigurasi
+// @Tags AchievementType
+// @Accept json
+// @Produce json
+// @Param code path string true "Achievement type code"
+// @Param request body map[string]interface{} true "Contoh details"
+// @Success 200 {object} model.PointsPreviewResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievement-types/{code}/points-preview [post]
+func (s *AchievementTypeService) PreviewPoints(c *fiber.Ctx) error {
+	t, err := s.Repo.GetByCode(c.Params("code"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "jenis prestasi tidak ditemukan"})
+	}
+
+	details := map[string]interface{}{}
+	if err := c.BodyParser(&details); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	var resp model.PointsPreviewResponse
+	resp.Status = "success"
+	resp.Data.AchievementType = t.Code
+	resp.Data.SuggestedPoints = calculatePoints(*t, details)
+
+	return c.JSON(resp)
+}
+
+// Deactivate godoc
+// @Summary Nonaktifkan jenis prestasi
+// @Description Jenis prestasi tidak dihapus; yang nonaktif tidak bisa dipakai untuk draft baru
+// @Tags AchievementType
+// @Produce json
+// @Param code path string true "Achievement type code"
+// @Success 200 {object} model.MessageResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievement-types/{code} [delete]
+func (s *AchievementTypeService) Deactivate(c *fiber.Ctx) error {
+	err := s.Repo.SetActive(c.Params("code"), false)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "jenis prestasi tidak ditemukan"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal menonaktifkan jenis prestasi"})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "jenis prestasi dinonaktifkan",
+	})
+}
+
+// validateAchievementDetails memeriksa details terhadap schema di katalog tipe prestasi.
+// requireAll=false dipakai untuk draft (boleh belum lengkap), true saat submit.
+func validateAchievementDetails(achievementType model.AchievementType, details map[string]interface{}, requireAll bool) []model.DetailFieldError {
+	schema := achievementType.Schema
+	errs := []model.DetailFieldError{}
+	known := map[string]bool{}
+
+	for _, field := range schema.Fields {
+		known[field.Name] = true
+
+		value, ok := details[field.Name]
+		if !ok || isEmptyDetail(value) {
+			if field.Required && requireAll {
+				errs = append(errs, model.DetailFieldError{Field: field.Name, Message: "wajib diisi"})
+			}
+			continue
+		}
+
+		if msg := validateDetailValue(field, value); msg != "" {
+			errs = append(errs, model.DetailFieldError{Field: field.Name, Message: msg})
+		}
+	}
+
+	if !schema.AllowExtra {
+		for name := range details {
+			if !known[name] {
+				errs = append(errs, model.DetailFieldError{Field: name, Message: "field tidak dikenal untuk tipe " + achievementType.Code})
+			}
+		}
+	}
+
+	// pasangan tanggal xxxStart / xxxEnd: akhir tidak boleh sebelum awal
+	for _, field := range schema.Fields {
+		prefix, ok := strings.CutSuffix(field.Name, "End")
+		if field.Type != model.FieldDate || !ok {
+			continue
+		}
+		start, okStart := parseDetailDate(details[prefix+"Start"])
+		end, okEnd := parseDetailDate(details[field.Name])
+		if okStart && okEnd && end.Before(start) {
+			errs = append(errs, model.DetailFieldError{Field: field.Name, Message: "tidak boleh sebelum " + prefix + "Start"})
+		}
+	}
+
+	slices.SortFunc(errs, func(a, b model.DetailFieldError) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+
+	return errs
+}
+
+func validateDetailValue(field model.AchievementField, value interface{}) string {
+	switch field.Type {
+	case model.FieldString:
+		if _, ok := value.(string); !ok {
+			return "harus berupa teks"
+		}
+
+	case model.FieldEnum:
+		str, ok := value.(string)
+		if !ok || !slices.Contains(field.Enum, str) {
+			return "harus salah satu dari: " + strings.Join(field.Enum, ", ")
+		}
+
+	case model.FieldDate:
+		if _, ok := parseDetailDate(value); !ok {
+			return "format tanggal harus YYYY-MM-DD"
+		}
+
+	case model.FieldNumber:
+		num, ok := detailNumber(value)
+		if !ok {
+			return "harus berupa angka"
+		}
+		if field.Min != nil && num < *field.Min {
+			return fmt.Sprintf("minimal %v", *field.Min)
+		}
+
+	case model.FieldStringArray:
+		items, ok := detailArray(value)
+		if !ok || len(items) == 0 {
+			return "harus berupa daftar teks"
+		}
+		for _, item := range items {
+			if str, ok := item.(string); !ok || strings.TrimSpace(str) == "" {
+				return "harus berupa daftar teks"
+			}
+		}
+	}
+
+	return ""
+}
+
+func isEmptyDetail(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if str, ok := value.(string); ok {
+		return strings.TrimSpace(str) == ""
+	}
+	return false
+}
+
+func parseDetailDate(value interface{}) (time.Time, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", str)
+	return t, err == nil
+}
+
+// angka dari body JSON berupa float64, dari MongoDB bisa int32/int64
+func detailNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func detailArray(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case primitive.A:
+		return v, true
+	}
+	return nil, false
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"uas-prestasi/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// field dokumen yang tidak ikut dibandingkan di diff
+var diffIgnoredFields = map[string]bool{
+	"_id":       true,
+	"version":   true,
+	"updatedAt": true,
+}
+
+// snapshot setiap edit (selain setiap submit) diaktifkan lewat ACHIEVEMENT_SNAPSHOT_EDITS=true
+func snapshotEditsFromEnv() bool {
+	v, _ := strconv.ParseBool(os.Getenv("ACHIEVEMENT_SNAPSHOT_EDITS"))
+	return v
+}
+
+// saveSnapshot menyalin dokumen sebagai versi tertentu; nil-safe untuk test
+func (s *AchievementService) saveSnapshot(mongoID string, doc bson.M, version int, reason, actorUserID string) error {
+	if s.VersionRepo == nil {
+		return nil
+	}
+
+	document := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		document[k] = v
+	}
+	document["version"] = version
+
+	return s.VersionRepo.Save(model.AchievementSnapshot{
+		AchievementID: mongoID,
+		Version:       version,
+		Reason:        reason,
+		CreatedAt:     time.Now(),
+		CreatedBy:     actorUserID,
+		Document:      document,
+	})
+}
+
+// canViewAchievement: hanya pemilik, dosen wali mahasiswa pemilik, atau admin
+// (achievement:list:all) yang program studi mahasiswanya ada di org scope.
+// Selain itu ditolak; error dikembalikan supaya tidak dianggap boleh.
+func (s *AchievementService) canViewAchievement(c *fiber.Ctx, ref *model.AchievementReference) (bool, error) {
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
+
+	studentID, err := s.RefRepo.GetStudentIDByUser(userID)
+	if err == nil {
+		return studentID == ref.StudentID, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+
+	isAdvisor, err := s.RefRepo.IsAdvisorOf(userID, ref.StudentID)
+	if err != nil || isAdvisor {
+		return isAdvisor, err
+	}
+
+	isAdmin, err := s.PermissionService.HasPermission(roleID, "achievement:list:all")
+	if err != nil || !isAdmin {
+		return false, err
+	}
+
+	programID, err := s.RefRepo.GetStudentProgramID(ref.StudentID)
+	if err != nil {
+		return false, err
+	}
+	return inScope(orgScope(c), programID), nil
+}
+
+// viewAccessError menerjemahkan hasil canViewAchievement ke response
+func viewAccessError(c *fiber.Ctx, err error) error {
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "Gagal memeriksa akses prestasi"})
+	}
+	return c.Status(403).JSON(fiber.Map{"error": "forbidden"})
+}
+
+// Versions godoc
+// @Summary Achievement versions
+// @Description Daftar snapshot dokumen prestasi. Snapshot dibuat setiap submit (dan setiap edit
+// kalau ACHIEVEMENT_SNAPSHOT_EDITS=true). verified_version adalah versi yang diverifikasi dosen wali.
+// @Tags Achievement
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} model.AchievementVersionsResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/{id}/versions [get]
+func (s *AchievementService) Versions(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	ref, err := s.RefRepo.GetByID(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"message": "Achievement tidak ditemukan"})
+	}
+	if ok, err := s.canViewAchievement(c, ref); !ok {
+		return viewAccessError(c, err)
+	}
+
+	snapshots, err := s.VersionRepo.List(ref.MongoAchievementID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "Gagal mengambil versi prestasi"})
+	}
+
+	submissions, err := s.RefRepo.GetSubmissions(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "Gagal mengambil data pengajuan"})
+	}
+
+	var resp model.AchievementVersionsResponse
+	resp.Status = "success"
+	resp.Data.Versions = versionItems(snapshots, submissions)
+	resp.Data.VerifiedVersion = verifiedVersion(submissions)
+
+	if current, err := s.MongoRepo.FindByID(ref.MongoAchievementID); err == nil {
+		resp.Data.CurrentVersion = docVersion(current)
+	}
+
+	return c.JSON(resp)
+}
+
+// Version godoc
+// @Summary Achievement version detail
+// @Description Isi lengkap satu snapshot dokumen prestasi
+// @Tags Achievement
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param version path int true "Nomor versi"
+// @Success 200 {object} model.AchievementVersionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/{id}/versions/{version} [get]
+func (s *AchievementService) Version(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "version harus berupa angka"})
+	}
+
+	ref, err := s.RefRepo.GetByID(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"message": "Achievement tidak ditemukan"})
+	}
+	if ok, err := s.canViewAchievement(c, ref); !ok {
+		return viewAccessError(c, err)
+	}
+
+	snapshot, err := s.VersionRepo.Get(ref.MongoAchievementID, version)
+	if err == mongo.ErrNoDocuments {
+		return c.Status(404).JSON(fiber.Map{"message": "Versi tidak ditemukan"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "Gagal mengambil versi prestasi"})
+	}
+	snapshot.Document, _ = plainValue(snapshot.Document).(map[string]interface{})
+
+	return c.JSON(model.AchievementVersionResponse{
+		Status: "success",
+		Data:   *snapshot,
+	})
+}
+
+// Diff godoc
+// @Summary Diff between achievement versions
+// @Description Perbedaan isi dokumen antara dua versi. to=current membandingkan dengan dokumen saat ini.
+// Tanpa parameter, dibandingkan dua pengajuan terakhir (misalnya sebelum ditolak dan setelah diajukan ulang).
+// @Tags Achievement
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param from query int false "Versi awal"
+// @Param to query string false "Versi akhir atau current"
+// @Success 200 {object} model.AchievementDiffResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/achievements/{id}/diff [get]
+func (s *AchievementService) Diff(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	ref, err := s.RefRepo.GetByID(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"message": "Achievement tidak ditemukan"})
+	}
+	if ok, err := s.canViewAchievement(c, ref); !ok {
+		return viewAccessError(c, err)
+	}
+
+	fromVersion, toVersion := c.Query("from"), c.Query("to")
+	if fromVersion == "" && toVersion == "" {
+		submissions, err := s.RefRepo.GetSubmissions(id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "Gagal mengambil data pengajuan"})
+		}
+		from, to, ok := lastTwoSubmittedVersions(submissions)
+		if !ok {
+			return c.Status(400).JSON(fiber.Map{"message": "Belum ada dua pengajuan untuk dibandingkan, isi from dan to"})
+		}
+		fromVersion, toVersion = strconv.Itoa(from), strconv.Itoa(to)
+	}
+	if toVersion == "" {
+		toVersion = "current"
+	}
+
+	fromDoc, from, err := s.loadVersion(ref.MongoAchievementID, fromVersion)
+	if err != nil {
+		return versionLoadError(c, err)
+	}
+	toDoc, to, err := s.loadVersion(ref.MongoAchievementID, toVersion)
+	if err != nil {
+		return versionLoadError(c, err)
+	}
+
+	var resp model.AchievementDiffResponse
+	resp.Status = "success"
+	resp.Data.From = from
+	resp.Data.To = to
+	resp.Data.Changes = diffDocuments(fromDoc, toDoc)
+
+	return c.JSON(resp)
+}
+
+type invalidVersionError string
+
+func (e invalidVersionError) Error() string { return string(e) }
+
+// loadVersion membaca snapshot, atau dokumen saat ini untuk "current"
+func (s *AchievementService) loadVersion(mongoID, value string) (map[string]interface{}, int, error) {
+	if value == "current" {
+		doc, err := s.MongoRepo.FindByID(mongoID)
+		if err != nil {
+			return nil, 0, err
+		}
+		plain, _ := plainValue(doc).(map[string]interface{})
+		return plain, docVersion(doc), nil
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, 0, invalidVersionError("from/to harus berupa nomor versi atau current")
+	}
+
+	snapshot, err := s.VersionRepo.Get(mongoID, version)
+	if err != nil {
+		return nil, 0, err
+	}
+	plain, _ := plainValue(snapshot.Document).(map[string]interface{})
+	return plain, version, nil
+}
+
+func versionLoadError(c *fiber.Ctx, err error) error {
+	if e, ok := err.(invalidVersionError); ok {
+		return c.Status(400).JSON(fiber.Map{"message": string(e)})
+	}
+	if err == mongo.ErrNoDocuments {
+		return c.Status(404).JSON(fiber.Map{"message": "Versi tidak ditemukan"})
+	}
+	return c.Status(500).JSON(fiber.Map{"message": "Gagal mengambil versi prestasi"})
+}
+
+// versionItems menggabungkan snapshot Mongo dengan pengajuan di Postgres
+func versionItems(snapshots []model.AchievementSnapshot, submissions []model.AchievementSubmission) []model.AchievementVersionItem {
+	byVersion := map[int]model.AchievementSubmission{}
+	for _, sub := range submissions {
+		if sub.DocumentVersion != nil {
+			byVersion[*sub.DocumentVersion] = sub
+		}
+	}
+	verified := verifiedVersion(submissions)
+
+	items := make([]model.AchievementVersionItem, 0, len(snapshots))
+	for _, snap := range snapshots {
+		item := model.AchievementVersionItem{
+			Version:   snap.Version,
+			Reason:    snap.Reason,
+			CreatedAt: snap.CreatedAt,
+			CreatedBy: snap.CreatedBy,
+			Verified:  verified != nil && *verified == snap.Version,
+		}
+		if sub, ok := byVersion[snap.Version]; ok {
+			no := sub.SubmissionNo
+			item.SubmissionNo = &no
+			item.Decision = sub.Decision
+			item.Note = sub.Note
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// verifiedVersion: versi dokumen dari pengajuan terakhir yang diverifikasi
+func verifiedVersion(submissions []model.AchievementSubmission) *int {
+	for i := len(submissions) - 1; i >= 0; i-- {
+		sub := submissions[i]
+		if sub.Decision != nil && *sub.Decision == model.StatusVerified {
+			return sub.DocumentVersion
+		}
+	}
+	return nil
+}
+
+func lastTwoSubmittedVersions(submissions []model.AchievementSubmission) (int, int, bool) {
+	versions := []int{}
+	for _, sub := range submissions {
+		if sub.DocumentVersion != nil {
+			versions = append(versions, *sub.DocumentVersion)
+		}
+	}
+	if len(versions) < 2 {
+		return 0, 0, false
+	}
+	return versions[len(versions)-2], versions[len(versions)-1], true
+}
+
+// diffDocuments membandingkan dua dokumen secara rekursif, urut path
+func diffDocuments(from, to map[string]interface{}) []model.DiffChange {
+	changes := []model.DiffChange{}
+
+	a, b := map[string]interface{}{}, map[string]interface{}{}
+	for k, v := range from {
+		if !diffIgnoredFields[k] {
+			a[k] = v
+		}
+	}
+	for k, v := range to {
+		if !diffIgnoredFields[k] {
+			b[k] = v
+		}
+	}
+
+	diffValues("", a, b, &changes)
+	return changes
+}
+
+func diffValues(path string, from, to interface{}, changes *[]model.DiffChange) {
+	fromObj, fromIsObj := from.(map[string]interface{})
+	toObj, toIsObj := to.(map[string]interface{})
+	if fromIsObj && toIsObj {
+		keys := map[string]bool{}
+		for k := range fromObj {
+			keys[k] = true
+		}
+		for k := range toObj {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			fv, inFrom := fromObj[k]
+			tv, inTo := toObj[k]
+			switch {
+			case !inFrom:
+				*changes = append(*changes, model.DiffChange{Path: child, Op: model.DiffAdded, To: tv})
+			case !inTo:
+				*changes = append(*changes, model.DiffChange{Path: child, Op: model.DiffRemoved, From: fv})
+			default:
+				diffValues(child, fv, tv, changes)
+			}
+		}
+		return
+	}
+
+	fromArr, fromIsArr := from.([]interface{})
+	toArr, toIsArr := to.([]interface{})
+	if fromIsArr && toIsArr {
+		for i := 0; i < max(len(fromArr), len(toArr)); i++ {
+			child := fmt.Sprintf("%s.%d", path, i)
+			switch {
+			case i >= len(fromArr):
+				*changes = append(*changes, model.DiffChange{Path: child, Op: model.DiffAdded, To: toArr[i]})
+			case i >= len(toArr):
+				*changes = append(*changes, model.DiffChange{Path: child, Op: model.DiffRemoved, From: fromArr[i]})
+			default:
+				diffValues(child, fromArr[i], toArr[i], changes)
+			}
+		}
+		return
+	}
+
+	if !sameValue(from, to) {
+		*changes = append(*changes, model.DiffChange{Path: strings.TrimPrefix(path, "."), Op: model.DiffChanged, From: from, To: to})
+	}
+}
+
+// sameValue menyamakan tipe angka dari Mongo (int32/int64/float64) sebelum dibandingkan
+func sameValue(a, b interface{}) bool {
+	if na, ok := detailNumber(a); ok {
+		if nb, ok := detailNumber(b); ok {
+			return na == nb
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// newViewerApp memasang handler yang memakai canViewAchievement, dengan locals
+// seperti hasil JWT + OrgScope
+func newViewerApp(service *AchievementService, userID, roleID string, scope []string) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", userID)
+		c.Locals("role_id", roleID)
+		c.Locals("org_scope", scope)
+		return c.Next()
+	})
+	app.Get("/achievements/:id/history", service.History)
+	app.Get("/achievements/:id/versions", service.Versions)
+	app.Get("/achievements/:id/comments", service.Comments)
+	app.Post("/achievements/:id/comments", service.AddComment)
+	return app
+}
+
+// expectReference: reference a1 milik mahasiswa s1
+func expectReference(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT id, student_id, mongo_achievement_id, status, created_at`).
+		WithArgs("a1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "created_at",
+			"suggested_points", "awarded_points", "points_override_reason"}).
+			AddRow("a1", "s1", "m1", "submitted", time.Now(), 68, nil, nil))
+}
+
+// expectNotStudentNorAdvisor: user bukan mahasiswa dan bukan dosen wali s1
+func expectNotStudentNorAdvisor(mock sqlmock.Sqlmock, userID string) {
+	mock.ExpectQuery(`SELECT id FROM students WHERE user_id = \$1`).
+		WithArgs(userID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`FROM students s[\s\S]+JOIN lecturers l`).
+		WithArgs("s1", userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+}
+
+func expectPermissions(mock sqlmock.Sqlmock, roleID string, perms ...string) {
+	rows := sqlmock.NewRows([]string{"name"})
+	for _, p := range perms {
+		rows.AddRow(p)
+	}
+	mock.ExpectQuery(`FROM permissions p`).WithArgs(roleID).WillReturnRows(rows)
+}
+
+func newViewerService(db *sql.DB) *AchievementService {
+	return NewAchievementService(nil, repository.NewAchievementReferenceRepository(db), nil,
+		NewPermissionService(repository.NewPermissionRepository(db)), nil, nil, nil, nil, nil)
+}
+
+func TestVersions_StrangerLecturerForbidden(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectReference(mock)
+	expectNotStudentNorAdvisor(mock, "dosen-lain")
+	expectPermissions(mock, "role-dosen", "achievement:detail", "achievement:verify")
+
+	app := newViewerApp(newViewerService(db), "dosen-lain", "role-dosen", []string{})
+	resp, err := app.Test(httptest.NewRequest("GET", "/achievements/a1/versions", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVersions_AdminOutsideScopeForbidden(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectReference(mock)
+	expectNotStudentNorAdvisor(mock, "admin-fakultas")
+	expectPermissions(mock, "role-admin", "achievement:detail", "achievement:list:all")
+	mock.ExpectQuery(`SELECT study_program_id FROM students WHERE id = \$1`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"study_program_id"}).AddRow("prodi-lain"))
+
+	app := newViewerApp(newViewerService(db), "admin-fakultas", "role-admin", []string{"prodi-1"})
+	resp, err := app.Test(httptest.NewRequest("GET", "/achievements/a1/versions", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVersions_LookupErrorIsNotAccess(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectReference(mock)
+	mock.ExpectQuery(`SELECT id FROM students WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnError(sql.ErrConnDone)
+
+	app := newViewerApp(newViewerService(db), "u1", "role-mahasiswa", []string{})
+	resp, err := app.Test(httptest.NewRequest("GET", "/achievements/a1/versions", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDiffDocuments(t *testing.T) {
+	before := plainValue(bson.M{
+		"_id":         "m1",
+		"version":     int32(3),
+		"title":       "Juara 2 Gemastik",
+		"description": "lama",
+		"points":      int32(0),
+		"tags":        bson.A{"ai", "lomba"},
+		"details":     bson.M{"rank": int32(2), "organizer": "Kemdikbud"},
+	}).(map[string]interface{})
+	after := map[string]interface{}{
+		"_id":     "m1",
+		"version": float64(6),
+		"title":   "Juara 1 Gemastik",
+		"points":  float64(0),
+		"tags":    []interface{}{"ai", "lomba", "nasional"},
+		"details": map[string]interface{}{"rank": float64(1), "organizer": "Kemdikbud", "medalType": "gold"},
+	}
+
+	assert.Equal(t, []model.DiffChange{
+		{Path: "description", Op: model.DiffRemoved, From: "lama"},
+		{Path: "details.medalType", Op: model.DiffAdded, To: "gold"},
+		{Path: "details.rank", Op: model.DiffChanged, From: int32(2), To: float64(1)},
+		{Path: "tags.2", Op: model.DiffAdded, To: "nasional"},
+		{Path: "title", Op: model.DiffChanged, From: "Juara 2 Gemastik", To: "Juara 1 Gemastik"},
+	}, diffDocuments(before, after))
+}
+
+func TestVersionItems_MarksVerifiedSubmission(t *testing.T) {
+	rejected, verified := model.StatusRejected, model.StatusVerified
+	v2, v5 := 2, 5
+
+	submissions := []model.AchievementSubmission{
+		{SubmissionNo: 1, DocumentVersion: &v2, Decision: &rejected},
+		{SubmissionNo: 2, DocumentVersion: &v5, Decision: &verified},
+	}
+	snapshots := []model.AchievementSnapshot{
+		{Version: 2, Reason: model.SnapshotReasonSubmit, CreatedAt: time.Now()},
+		{Version: 4, Reason: model.SnapshotReasonEdit, CreatedAt: time.Now()},
+		{Version: 5, Reason: model.SnapshotReasonSubmit, CreatedAt: time.Now()},
+	}
+
+	items := versionItems(snapshots, submissions)
+	assert.Len(t, items, 3)
+	assert.Equal(t, 1, *items[0].SubmissionNo)
+	assert.Equal(t, rejected, *items[0].Decision)
+	assert.False(t, items[0].Verified)
+	assert.Nil(t, items[1].SubmissionNo)
+	assert.True(t, items[2].Verified)
+	assert.Equal(t, 5, *verifiedVersion(submissions))
+
+	from, to, ok := lastTwoSubmittedVersions(submissions)
+	assert.True(t, ok)
+	assert.Equal(t, 2, from)
+	assert.Equal(t, 5, to)
+
+	// pengajuan lama tanpa document_version tidak bisa dibandingkan
+	_, _, ok = lastTwoSubmittedVersions([]model.AchievementSubmission{{SubmissionNo: 1}, {SubmissionNo: 2, DocumentVersion: &v5}})
+	assert.False(t, ok)
+}
+
+func TestHistory_AdminOutsideScopeForbidden(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectReference(mock)
+	expectNotStudentNorAdvisor(mock, "admin-jurusan")
+	expectPermissions(mock, "role-admin", "achievement:history", "achievement:list:all")
+	mock.ExpectQuery(`SELECT study_program_id FROM students WHERE id = \$1`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"study_program_id"}).AddRow("prodi-lain"))
+
+	app := newViewerApp(newViewerService(db), "admin-jurusan", "role-admin", []string{"dep-te", "prodi-ti"})
+	resp, err := app.Test(httptest.NewRequest("GET", "/achievements/a1/history", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
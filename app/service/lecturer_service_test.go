@@ -1,16 +1,22 @@
 package service
 
 import (
+	"database/sql"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"uas-prestasi/app/repository"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
+var lecturerColumns = []string{"id", "user_id", "full_name", "email", "nidn", "org_unit_id", "department", "is_active", "advisee_count"}
+
 func TestGetLecturers_Success(t *testing.T) {
 	// mock DB
 	db, mock, err := sqlmock.New()
@@ -18,14 +24,11 @@ func TestGetLecturers_Success(t *testing.T) {
 	defer db.Close()
 
 	// mock rows
-	rows := sqlmock.NewRows([]string{"id", "user_id", "username"}).
-		AddRow("l1", "u1", "dosenA")
+	rows := sqlmock.NewRows(lecturerColumns).
+		AddRow("l1", "u1", "Budi Santoso", "dosenwali1@gmail.com", "0012345678", nil, "", true, 3)
 
-	mock.ExpectQuery(`
-		SELECT l.id, l.user_id, u.username
-		FROM lecturers l
-		JOIN users u ON u.id = l.user_id
-	`).WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT l.id, l.user_id, u.full_name, u.email`).
+		WillReturnRows(rows)
 
 	// init repo & service
 	lecturerRepo := repository.NewLecturerRepository(db)
@@ -83,3 +86,85 @@ func TestGetAdvisees_DBError(t *testing.T) {
 	assert.Equal(t, 500, resp.StatusCode)
 }
 
+
+func TestDeactivateLecturer_HasAdvisees(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	rows := sqlmock.NewRows(lecturerColumns).
+		AddRow("l1", "u1", "Budi Santoso", "dosenwali1@gmail.com", "0012345678", nil, "", true, 2)
+
+	mock.ExpectQuery(`FROM lecturers l .* WHERE l.id = \$1`).
+		WithArgs("l1").
+		WillReturnRows(rows)
+
+	lecturerRepo := repository.NewLecturerRepository(db)
+	service := NewStudentService(nil, lecturerRepo)
+
+	app := fiber.New()
+	app.Delete("/lecturers/:id", service.DeactivateLecturer)
+
+	req := httptest.NewRequest("DELETE", "/lecturers/l1", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateLecturer_DuplicateNIDN(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM lecturers WHERE user_id = \$1\)`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(`INSERT INTO lecturers`).
+		WithArgs("u1", "0012345678", nil).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "lecturers_nidn_key"})
+
+	service := NewStudentService(nil, repository.NewLecturerRepository(db))
+
+	app := fiber.New()
+	app.Post("/lecturers", service.CreateLecturer)
+
+	req := httptest.NewRequest("POST", "/lecturers", strings.NewReader(`{"user_id":"u1","nidn":"0012345678"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSemesterStart(t *testing.T) {
+	loc := time.UTC
+
+	assert.Equal(t, time.Date(2026, time.August, 1, 0, 0, 0, 0, loc),
+		semesterStart(time.Date(2026, time.October, 19, 10, 0, 0, 0, loc)))
+	assert.Equal(t, time.Date(2025, time.August, 1, 0, 0, 0, 0, loc),
+		semesterStart(time.Date(2026, time.January, 15, 10, 0, 0, 0, loc)))
+	assert.Equal(t, time.Date(2026, time.February, 1, 0, 0, 0, 0, loc),
+		semesterStart(time.Date(2026, time.May, 2, 10, 0, 0, 0, loc)))
+}
+
+func TestGetMyDashboard_NotLecturer(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM lecturers l .* WHERE l.user_id = \$1`).
+		WithArgs("u9").
+		WillReturnError(sql.ErrNoRows)
+
+	service := NewStudentService(nil, repository.NewLecturerRepository(db))
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "u9")
+		return c.Next()
+	})
+	app.Get("/lecturers/me/dashboard", service.GetMyDashboard)
+
+	req := httptest.NewRequest("GET", "/lecturers/me/dashboard", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 403, resp.StatusCode)
+}
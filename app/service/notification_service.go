@@ -0,0 +1,107 @@
+package service
+
+import (
+	"database/sql"
+	"strconv"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationService struct {
+	Repo *repository.NotificationRepository
+}
+
+func NewNotificationService(repo *repository.NotificationRepository) *NotificationService {
+	return &NotificationService{Repo: repo}
+}
+
+// List godoc
+// @Summary List my notifications
+// @Description Notifikasi milik user yang login, terbaru lebih dulu
+// @Tags Notification
+// @Produce json
+// @Param unread query bool false "Hanya yang belum dibaca"
+// @Param limit query int false "Jumlah data (maks 100)" default(20)
+// @Success 200 {object} model.NotificationListResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/notifications [get]
+func (s *NotificationService) List(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	data, err := s.Repo.GetByUser(userID, c.QueryBool("unread"), limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil notifikasi"})
+	}
+
+	unread, err := s.Repo.CountUnread(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil notifikasi"})
+	}
+
+	resp := model.NotificationListResponse{Status: "success", Data: data}
+	resp.Meta.Unread = unread
+
+	return c.JSON(resp)
+}
+
+// MarkRead godoc
+// @Summary Mark notification as read
+// @Tags Notification
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} model.MessageResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/notifications/{id}/read [put]
+func (s *NotificationService) MarkRead(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+	id := c.Params("id")
+
+	if !isUUID(id) {
+		return c.Status(404).JSON(fiber.Map{"error": "notifikasi tidak ditemukan"})
+	}
+
+	err := s.Repo.MarkRead(id, userID)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "notifikasi tidak ditemukan"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal memperbarui notifikasi"})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "notifikasi ditandai sudah dibaca",
+	})
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Tags Notification
+// @Produce json
+// @Success 200 {object} model.MessageResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/notifications/read-all [put]
+func (s *NotificationService) MarkAllRead(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	if err := s.Repo.MarkAllRead(userID); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal memperbarui notifikasi"})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "semua notifikasi ditandai sudah dibaca",
+	})
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"uas-prestasi/app/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkNotificationRead_MalformedID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	service := NewNotificationService(repository.NewNotificationRepository(db))
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "u1")
+		return c.Next()
+	})
+	app.Put("/notifications/:id/read", service.MarkRead)
+
+	req := httptest.NewRequest("PUT", "/notifications/bukan-uuid/read", nil)
+	resp, _ := app.Test(req)
+
+	// tidak ada query ke Postgres untuk id yang bukan UUID
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
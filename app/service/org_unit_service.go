@@ -0,0 +1,437 @@
+package service
+
+import (
+	"database/sql"
+	"slices"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrgUnitService struct {
+	Repo *repository.OrgUnitRepository
+}
+
+func NewOrgUnitService(repo *repository.OrgUnitRepository) *OrgUnitService {
+	return &OrgUnitService{Repo: repo}
+}
+
+// permission akses tipe admin; hanya role dengan salah satunya yang dibatasi unit
+var orgScopedPermissions = []string{"achievement:list:all", "user:manage", "org:manage"}
+
+// ScopeOf mengembalikan id unit (beserta turunannya) yang boleh diakses user.
+// nil untuk super admin (users.is_super_admin) dan untuk role non-admin (dosen,
+// mahasiswa) yang aksesnya sudah dibatasi lewat kepemilikan/perwalian, bukan unit.
+// Admin tanpa unit mendapat scope kosong sehingga tidak melihat data unit mana pun.
+func (s *OrgUnitService) ScopeOf(userID string) ([]string, error) {
+	unitID, superAdmin, scoped, err := s.Repo.GetUserScope(userID, orgScopedPermissions)
+	if err != nil {
+		return nil, err
+	}
+	if superAdmin || !scoped {
+		return nil, nil
+	}
+	if unitID == nil {
+		return []string{}, nil
+	}
+
+	ids, err := s.Repo.GetDescendantIDs(*unitID)
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	return ids, nil
+}
+
+// orgScope membaca scope yang sudah diisi middleware.OrgScope
+func orgScope(c *fiber.Ctx) []string {
+	scope, _ := c.Locals("org_scope").([]string)
+	return scope
+}
+
+func inScope(scope []string, unitID *string) bool {
+	if scope == nil {
+		return true
+	}
+	if unitID == nil {
+		return false
+	}
+	return slices.Contains(scope, *unitID)
+}
+
+func (s *OrgUnitService) validate(req model.OrgUnitRequest) (string, int) {
+	parentType, ok := model.OrgUnitParentType[req.Type]
+	if !ok {
+		return "type harus faculty, department, atau study_program", 400
+	}
+	if req.Code == "" || req.Name == "" {
+		return "code dan name wajib diisi", 400
+	}
+
+	if parentType == "" {
+		if req.ParentID != nil {
+			return "fakultas tidak boleh memiliki parent", 400
+		}
+		return "", 0
+	}
+
+	if req.ParentID == nil {
+		return req.Type + " wajib memiliki parent " + parentType, 400
+	}
+
+	parent, err := s.Repo.GetByID(*req.ParentID)
+	if err == sql.ErrNoRows {
+		return "parent tidak ditemukan", 400
+	}
+	if err != nil {
+		return "gagal memvalidasi parent", 500
+	}
+	if parent.Type != parentType {
+		return req.Type + " harus berada di bawah " + parentType, 400
+	}
+
+	return "", 0
+}
+
+// List godoc
+// @Summary List unit organisasi
+// @Description Menampilkan fakultas, jurusan, dan program studi (dibatasi scope unit user)
+// @Tags OrgUnit
+// @Produce json
+// @Param type query string false "Filter jenis (faculty, department, study_program)"
+// @Param parent_id query string false "Filter parent"
+// @Success 200 {object} model.OrgUnitListResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/org-units [get]
+func (s *OrgUnitService) List(c *fiber.Ctx) error {
+	data, err := s.Repo.GetAll(c.Query("type"), c.Query("parent_id"), orgScope(c))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil unit organisasi"})
+	}
+
+	return c.JSON(model.OrgUnitListResponse{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+// Get godoc
+// @Summary Detail unit organisasi
+// @Tags OrgUnit
+// @Produce json
+// @Param id path string true "Org unit ID"
+// @Success 200 {object} model.OrgUnitResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/org-units/{id} [get]
+func (s *OrgUnitService) Get(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	unit, err := s.Repo.GetByID(id)
+	if err != nil || !inScope(orgScope(c), &unit.ID) {
+		return c.Status(404).JSON(fiber.Map{"error": "unit tidak ditemukan"})
+	}
+
+	return c.JSON(model.OrgUnitResponse{
+		Status: "success",
+		Data:   *unit,
+	})
+}
+
+// Create godoc
+// @Summary Tambah unit organisasi
+// @Description Fakultas tanpa parent, jurusan di bawah fakultas, program studi di bawah jurusan
+// @Tags OrgUnit
+// @Accept json
+// @Produce json
+// @Param request body model.OrgUnitRequest true "Data unit"
+// @Success 201 {object} model.OrgUnitResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/org-units [post]
+func (s *OrgUnitService) Create(c *fiber.Ctx) error {
+	var req model.OrgUnitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	if msg, code := s.validate(req); msg != "" {
+		return c.Status(code).JSON(fiber.Map{"error": msg})
+	}
+
+	// admin unit hanya boleh menambah sub-unit di dalam unitnya
+	if !inScope(orgScope(c), req.ParentID) {
+		return c.Status(403).JSON(fiber.Map{"error": "parent di luar unit Anda"})
+	}
+
+	unit := model.OrgUnit{
+		ParentID: req.ParentID,
+		Type:     req.Type,
+		Code:     req.Code,
+		Name:     req.Name,
+		IsActive: req.IsActive == nil || *req.IsActive,
+	}
+
+	if err := s.Repo.Create(&unit); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal menyimpan unit"})
+	}
+
+	return c.Status(201).JSON(model.OrgUnitResponse{
+		Status: "success",
+		Data:   unit,
+	})
+}
+
+// Update godoc
+// @Summary Update unit organisasi
+// @Tags OrgUnit
+// @Accept json
+// @Produce json
+// @Param id path string true "Org unit ID"
+// @Param request body model.OrgUnitRequest true "Data unit"
+// @Success 200 {object} model.OrgUnitResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/org-units/{id} [put]
+func (s *OrgUnitService) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+	scope := orgScope(c)
+
+	unit, err := s.Repo.GetByID(id)
+	if err != nil || !inScope(scope, &unit.ID) {
+		return c.Status(404).JSON(fiber.Map{"error": "unit tidak ditemukan"})
+	}
+
+	var req model.OrgUnitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	// jenis unit tidak bisa diubah
+	req.Type = unit.Type
+	if msg, code := s.validate(req); msg != "" {
+		return c.Status(code).JSON(fiber.Map{"error": msg})
+	}
+
+	if req.ParentID != nil && (*req.ParentID == unit.ID || !inScope(scope, req.ParentID)) {
+		return c.Status(400).JSON(fiber.Map{"error": "parent tidak valid"})
+	}
+
+	unit.ParentID = req.ParentID
+	unit.Code = req.Code
+	unit.Name = req.Name
+	if req.IsActive != nil {
+		unit.IsActive = *req.IsActive
+	}
+
+	if err := s.Repo.Update(unit); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal update unit"})
+	}
+
+	updated, err := s.Repo.GetByID(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "unit tidak ditemukan"})
+	}
+
+	return c.JSON(model.OrgUnitResponse{
+		Status: "success",
+		Data:   *updated,
+	})
+}
+
+// Delete godoc
+// @Summary Hapus unit organisasi
+// @Description Unit hanya bisa dihapus jika tidak memiliki sub-unit, mahasiswa, dosen, atau user
+// @Tags OrgUnit
+// @Produce json
+// @Param id path string true "Org unit ID"
+// @Success 200 {object} model.MessageResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/org-units/{id} [delete]
+func (s *OrgUnitService) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	scope := orgScope(c)
+
+	// root scope sendiri tidak boleh dihapus oleh admin unit tsb
+	if len(scope) > 0 && scope[0] == id {
+		return c.Status(403).JSON(fiber.Map{"error": "tidak dapat menghapus unit Anda sendiri"})
+	}
+	if !inScope(scope, &id) {
+		return c.Status(404).JSON(fiber.Map{"error": "unit tidak ditemukan"})
+	}
+
+	err := s.Repo.Delete(id)
+	if err == repository.ErrOrgUnitInUse {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "unit tidak ditemukan"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal menghapus unit"})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "unit dihapus",
+	})
+}
+
+// cek unit tujuan ada, jenisnya sesuai, dan masih di dalam scope
+func (s *OrgUnitService) checkTarget(c *fiber.Ctx, unitID *string, allowedTypes ...string) (string, int) {
+	if unitID == nil {
+		return "", 0
+	}
+
+	unit, err := s.Repo.GetByID(*unitID)
+	if err == sql.ErrNoRows || (err == nil && !inScope(orgScope(c), &unit.ID)) {
+		return "unit tidak ditemukan", 404
+	}
+	if err != nil {
+		return "gagal memvalidasi unit", 500
+	}
+	if !slices.Contains(allowedTypes, unit.Type) {
+		return "jenis unit tidak sesuai", 400
+	}
+	if !unit.IsActive {
+		return "unit sudah tidak aktif", 400
+	}
+
+	return "", 0
+}
+
+func (s *OrgUnitService) assignResult(c *fiber.Ctx, err error, unitID *string) error {
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "data tidak ditemukan"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal menyimpan unit"})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":      "success",
+		"org_unit_id": unitID,
+	})
+}
+
+// AssignStudent godoc
+// @Summary Set program studi mahasiswa
+// @Description Menghubungkan mahasiswa ke program studi (null untuk melepas)
+// @Tags OrgUnit
+// @Accept json
+// @Produce json
+// @Param id path string true "Student ID"
+// @Param request body model.AssignOrgUnitRequest true "Program studi"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/students/{id}/study-program [put]
+func (s *OrgUnitService) AssignStudent(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req model.AssignOrgUnitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	// mahasiswa tanpa program studi hanya bisa diklaim admin tanpa scope
+	current, err := s.Repo.GetStudentUnit(id)
+	if err != nil || !inScope(orgScope(c), current) {
+		return c.Status(404).JSON(fiber.Map{"error": "student not found"})
+	}
+
+	if msg, code := s.checkTarget(c, req.OrgUnitID, model.OrgUnitStudyProgram); msg != "" {
+		return c.Status(code).JSON(fiber.Map{"error": msg})
+	}
+
+	return s.assignResult(c, s.Repo.AssignStudent(id, req.OrgUnitID), req.OrgUnitID)
+}
+
+// AssignLecturer godoc
+// @Summary Set unit dosen
+// @Description Menghubungkan dosen ke jurusan atau program studi (null untuk melepas)
+// @Tags OrgUnit
+// @Accept json
+// @Produce json
+// @Param id path string true "Lecturer ID"
+// @Param request body model.AssignOrgUnitRequest true "Unit"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/lecturers/{id}/org-unit [put]
+func (s *OrgUnitService) AssignLecturer(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req model.AssignOrgUnitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	current, err := s.Repo.GetLecturerUnit(id)
+	if err != nil || !inScope(orgScope(c), current) {
+		return c.Status(404).JSON(fiber.Map{"error": "lecturer not found"})
+	}
+
+	if msg, code := s.checkTarget(c, req.OrgUnitID, model.OrgUnitDepartment, model.OrgUnitStudyProgram); msg != "" {
+		return c.Status(code).JSON(fiber.Map{"error": msg})
+	}
+
+	return s.assignResult(c, s.Repo.AssignLecturer(id, req.OrgUnitID), req.OrgUnitID)
+}
+
+// AssignUser godoc
+// @Summary Set scope unit user
+// @Description Membatasi user (mis. admin jurusan) ke satu unit beserta turunannya.
+// null melepas unit; user tanpa unit tidak punya akses data unit kecuali super admin.
+// @Tags OrgUnit
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body model.AssignOrgUnitRequest true "Unit"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/{id}/org-unit [put]
+func (s *OrgUnitService) AssignUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	scope := orgScope(c)
+
+	var req model.AssignOrgUnitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	// admin unit tidak bisa membuat user tanpa batas
+	if scope != nil && req.OrgUnitID == nil {
+		return c.Status(403).JSON(fiber.Map{"error": "tidak dapat melepas scope user"})
+	}
+
+	if scope != nil {
+		ok, err := s.Repo.IsUserInScope(id, scope)
+		if err != nil || !ok {
+			return c.Status(404).JSON(fiber.Map{"error": "user not found"})
+		}
+	}
+
+	if msg, code := s.checkTarget(c, req.OrgUnitID, model.OrgUnitFaculty, model.OrgUnitDepartment, model.OrgUnitStudyProgram); msg != "" {
+		return c.Status(code).JSON(fiber.Map{"error": msg})
+	}
+
+	return s.assignResult(c, s.Repo.AssignUser(id, req.OrgUnitID), req.OrgUnitID)
+}
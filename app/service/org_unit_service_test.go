@@ -0,0 +1,127 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"uas-prestasi/app/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOrgUnit_DepartmentWithoutParent(t *testing.T) {
+	service := NewOrgUnitService(nil)
+
+	app := fiber.New()
+	app.Post("/org-units", service.Create)
+
+	body := `{"type":"department","code":"TE","name":"Teknik Elektro"}`
+	req := httptest.NewRequest("POST", "/org-units", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestCreateOrgUnit_ParentOutsideScope(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "parent_id", "type", "code", "name", "is_active", "created_at", "updated_at"}).
+		AddRow("dep-lain", "fak1", "department", "TS", "Teknik Sipil", true, time.Now(), time.Now())
+
+	mock.ExpectQuery(`FROM org_units\s+WHERE id = \$1`).
+		WithArgs("dep-lain").
+		WillReturnRows(rows)
+
+	service := NewOrgUnitService(repository.NewOrgUnitRepository(db))
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		// admin jurusan teknik elektro
+		c.Locals("org_scope", []string{"dep-te", "prodi-ti"})
+		return c.Next()
+	})
+	app.Post("/org-units", service.Create)
+
+	body := `{"type":"study_program","code":"RS","name":"Rekayasa Sipil","parent_id":"dep-lain"}`
+	req := httptest.NewRequest("POST", "/org-units", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 403, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScopeOf_UnitlessAdminIsNotUnrestricted(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT u.org_unit_id, u.is_super_admin`).
+		WithArgs("u-baru", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"org_unit_id", "is_super_admin", "scoped"}).AddRow(nil, false, true))
+	mock.ExpectQuery(`SELECT u.org_unit_id, u.is_super_admin`).
+		WithArgs("u-pusat", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"org_unit_id", "is_super_admin", "scoped"}).AddRow(nil, true, true))
+
+	service := NewOrgUnitService(repository.NewOrgUnitRepository(db))
+
+	scope, err := service.ScopeOf("u-baru")
+	assert.NoError(t, err)
+	assert.NotNil(t, scope)
+	assert.Empty(t, scope)
+
+	scope, err = service.ScopeOf("u-pusat")
+	assert.NoError(t, err)
+	assert.Nil(t, scope)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScopeOf_LecturerIsNotScopedByUnit(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	// dosen wali tanpa users.org_unit_id dan tanpa permission admin
+	mock.ExpectQuery(`SELECT u.org_unit_id, u.is_super_admin[\s\S]+p.name = ANY\(\$2\)`).
+		WithArgs("u-dosen", `{"achievement:list:all","user:manage","org:manage"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"org_unit_id", "is_super_admin", "scoped"}).AddRow(nil, false, false))
+
+	service := NewOrgUnitService(repository.NewOrgUnitRepository(db))
+
+	scope, err := service.ScopeOf("u-dosen")
+	assert.NoError(t, err)
+	assert.Nil(t, scope)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignStudent_ScopedAdminCannotClaimUnassigned(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT study_program_id FROM students WHERE id = \$1`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"study_program_id"}).AddRow(nil))
+
+	service := NewOrgUnitService(repository.NewOrgUnitRepository(db))
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("org_scope", []string{"dep-te", "prodi-ti"})
+		return c.Next()
+	})
+	app.Put("/students/:id/study-program", service.AssignStudent)
+
+	req := httptest.NewRequest("PUT", "/students/s1/study-program", strings.NewReader(`{"org_unit_id":"prodi-ti"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+)
+
+const (
+	defaultOutboxMaxAttempts = 10
+	outboxBatchSize          = 50
+	outboxPollInterval       = 5 * time.Second
+	// lebih lama dari timeout operasi Mongo di repository
+	outboxLease = time.Minute
+)
+
+// OutboxDispatcher menerapkan event outbox_events ke MongoDB.
+// Setiap event idempoten, jadi aman diproses ulang setelah crash atau lease habis.
+type OutboxDispatcher struct {
+	Repo        *repository.OutboxRepository
+	MongoRepo   *repository.AchievementMongoRepository
+	MaxAttempts int
+}
+
+func NewOutboxDispatcher(repo *repository.OutboxRepository, mongoRepo *repository.AchievementMongoRepository) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		Repo:        repo,
+		MongoRepo:   mongoRepo,
+		MaxAttempts: maxOutboxAttemptsFromEnv(),
+	}
+}
+
+func maxOutboxAttemptsFromEnv() int {
+	if v, err := strconv.Atoi(os.Getenv("OUTBOX_MAX_ATTEMPTS")); err == nil && v > 0 {
+		return v
+	}
+	return defaultOutboxMaxAttempts
+}
+
+// Start menjalankan polling di background sampai ctx dibatalkan
+func (d *OutboxDispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+
+		for {
+			d.DispatchPending()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// DispatchPending memproses satu batch event dan mengembalikan jumlah yang berhasil
+func (d *OutboxDispatcher) DispatchPending() int {
+	events, err := d.Repo.ClaimPending(outboxBatchSize, d.MaxAttempts, outboxLease)
+	if err != nil {
+		log.Println("outbox: gagal mengambil event:", err)
+		return 0
+	}
+
+	done := 0
+	for _, e := range events {
+		if d.process(e) == nil {
+			done++
+		}
+	}
+	return done
+}
+
+// DispatchOne langsung memproses event yang baru di-commit supaya Mongo
+// biasanya sudah konsisten saat response dikirim. Kalau gagal, event tetap
+// di outbox dan dicoba lagi oleh polling.
+func (d *OutboxDispatcher) DispatchOne(id string) error {
+	if d == nil {
+		return nil
+	}
+
+	e, err := d.Repo.ClaimByID(id, outboxLease)
+	if err == sql.ErrNoRows {
+		// sudah diproses atau sedang dipegang dispatcher lain
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return d.process(*e)
+}
+
+func (d *OutboxDispatcher) process(e model.OutboxEvent) error {
+	if err := d.apply(e); err != nil {
+		log.Printf("outbox: event %s (%s) gagal, percobaan ke-%d: %v", e.ID, e.EventType, e.Attempts+1, err)
+		if markErr := d.Repo.MarkFailed(e.ID, err.Error(), outboxBackoff(e.Attempts+1)); markErr != nil {
+			log.Println("outbox: gagal mencatat kegagalan:", markErr)
+		}
+		return err
+	}
+
+	return d.Repo.MarkProcessed(e.ID)
+}
+
+func (d *OutboxDispatcher) apply(e model.OutboxEvent) error {
+	switch e.EventType {
+	case model.OutboxAchievementDraftCreated:
+		var p model.DraftCreatedPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return err
+		}
+		return d.MongoRepo.UpsertDraft(p.MongoID, &p.Achievement)
+
+	case model.OutboxAchievementPointsAwarded:
+		var p model.PointsAwardedPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return err
+		}
+		return d.MongoRepo.UpdatePoints(p.MongoID, p.Points)
+	}
+
+	return fmt.Errorf("event type %q tidak dikenal", e.EventType)
+}
+
+// outboxBackoff: 2, 4, 8, ... detik, maksimal 10 menit
+func outboxBackoff(attempts int) time.Duration {
+	if attempts > 9 {
+		return 10 * time.Minute
+	}
+	backoff := time.Duration(1<<attempts) * time.Second
+	if backoff > 10*time.Minute {
+		return 10 * time.Minute
+	}
+	return backoff
+}
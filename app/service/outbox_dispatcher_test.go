@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"uas-prestasi/app/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, outboxBackoff(1))
+	assert.Equal(t, 8*time.Second, outboxBackoff(3))
+	assert.Equal(t, 10*time.Minute, outboxBackoff(10))
+	assert.Equal(t, 10*time.Minute, outboxBackoff(50))
+}
+
+func TestDispatchOne_NilDispatcher(t *testing.T) {
+	var d *OutboxDispatcher
+	assert.NoError(t, d.DispatchOne("e1"))
+}
+
+func TestDispatchOne_AlreadyClaimed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`UPDATE outbox_events[\s\S]+WHERE id = \$1`).
+		WithArgs("e1", 60).
+		WillReturnRows(sqlmock.NewRows(outboxColumns))
+
+	d := NewOutboxDispatcher(repository.NewOutboxRepository(db), nil)
+	assert.NoError(t, d.DispatchOne("e1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDispatchOne_FailedEventIsRescheduled(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`UPDATE outbox_events[\s\S]+WHERE id = \$1`).
+		WithArgs("e1", 60).
+		WillReturnRows(sqlmock.NewRows(outboxColumns).
+			AddRow("e1", "a1", "achievement.unknown", []byte(`{}`), 2, nil, now, nil, now))
+	mock.ExpectExec(`UPDATE outbox_events[\s\S]+attempts = attempts \+ 1`).
+		WithArgs("e1", sqlmock.AnyArg(), 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	d := NewOutboxDispatcher(repository.NewOutboxRepository(db), nil)
+	assert.Error(t, d.DispatchOne("e1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var outboxColumns = []string{"id", "aggregate_id", "event_type", "payload", "attempts", "last_error",
+	"available_at", "processed_at", "created_at"}
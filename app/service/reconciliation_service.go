@@ -0,0 +1,268 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// reference yang dibuat sekitar waktu snapshot Mongo diambil tidak dinilai
+const reconcileSettleMargin = time.Minute
+
+type ReconciliationService struct {
+	Repo              *repository.ReconciliationRepository
+	MongoRepo         *repository.AchievementMongoRepository
+	MaxOutboxAttempts int
+}
+
+func NewReconciliationService(repo *repository.ReconciliationRepository, mongoRepo *repository.AchievementMongoRepository) *ReconciliationService {
+	return &ReconciliationService{
+		Repo:              repo,
+		MongoRepo:         mongoRepo,
+		MaxOutboxAttempts: maxOutboxAttemptsFromEnv(),
+	}
+}
+
+// Reconcile godoc
+// @Summary Reconcile Postgres and MongoDB
+// @Description Membandingkan achievement_references dengan dokumen achievements dan melaporkan
+// reference tanpa dokumen, dokumen tanpa reference, serta perbedaan studentId, points, dan status.
+// Default dry_run=true (hanya laporan). repair memperbaiki dokumen Mongo mengikuti Postgres
+// dan mengantrekan ulang event outbox draft; quarantine memindahkan dokumen yatim ke
+// achievements_quarantine dan menandai reference tanpa dokumen sebagai deleted (hanya
+// yang masih draft/submitted). Field status lama di dokumen Mongo dihapus saat repair.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body model.ReconcileRequest false "Mode rekonsiliasi"
+// @Success 200 {object} model.ReconcileResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/reconciliation [post]
+func (s *ReconciliationService) Reconcile(c *fiber.Ctx) error {
+	var req model.ReconcileRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+		}
+	}
+
+	report, err := s.Run(req, c.Locals("user_id").(string))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "rekonsiliasi gagal: " + err.Error()})
+	}
+
+	return c.JSON(model.ReconcileResponse{
+		Status: "success",
+		Data:   *report,
+	})
+}
+
+// Run dipakai endpoint admin dan command cmd/reconcile.
+// actorUserID kosong kalau dijalankan dari CLI.
+func (s *ReconciliationService) Run(req model.ReconcileRequest, actorUserID string) (*model.ReconcileReport, error) {
+	report := &model.ReconcileReport{
+		DryRun:    req.DryRun == nil || *req.DryRun,
+		StartedAt: time.Now(),
+		Summary:   map[string]int{},
+	}
+
+	// dokumen diambil lebih dulu: dokumen hanya dibuat lewat outbox setelah
+	// reference-nya commit, jadi setiap dokumen di snapshot pasti punya reference
+	docs, err := s.Repo.ListDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := s.Repo.ListReferences(s.MaxOutboxAttempts, time.Since(report.StartedAt)+reconcileSettleMargin)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, skipped := findReconcileIssues(refs, docs)
+	for i := range issues {
+		s.resolve(&issues[i], req, report.DryRun, actorUserID)
+		report.Summary[issues[i].Kind]++
+	}
+
+	report.ReferencesScanned = len(refs)
+	report.DocumentsScanned = len(docs)
+	report.SkippedPending = skipped
+	report.Issues = issues
+	report.FinishedAt = time.Now()
+
+	return report, nil
+}
+
+// findReconcileIssues membandingkan kedua snapshot. Postgres dianggap sumber
+// kebenaran untuk studentId, status, dan poin yang sudah diverifikasi.
+func findReconcileIssues(refs []model.ReconcileReference, docs map[string]model.ReconcileDocument) ([]model.ReconcileIssue, int) {
+	issues := []model.ReconcileIssue{}
+	skipped := 0
+	referenced := map[string]bool{}
+
+	for _, ref := range refs {
+		referenced[ref.MongoID] = true
+
+		// event outbox masih jalan, perbedaan di Mongo belum tentu masalah
+		if ref.PendingOutbox {
+			skipped++
+			continue
+		}
+
+		doc, ok := docs[ref.MongoID]
+		if !ok {
+			issues = append(issues, model.ReconcileIssue{
+				Kind:        model.ReconcileMissingDocument,
+				ReferenceID: ref.ID,
+				MongoID:     ref.MongoID,
+				Postgres:    ref.Status,
+			})
+			continue
+		}
+
+		if doc.StudentID != ref.StudentID {
+			issues = append(issues, model.ReconcileIssue{
+				Kind:        model.ReconcileStudentMismatch,
+				ReferenceID: ref.ID,
+				MongoID:     ref.MongoID,
+				Postgres:    ref.StudentID,
+				Mongo:       doc.StudentID,
+			})
+		}
+
+		// poin hanya ada setelah verifikasi; reference verified lama tanpa
+		// awarded_points tidak bisa dibandingkan
+		expected, comparable := 0, true
+		if ref.Status == model.StatusVerified {
+			if ref.AwardedPoints == nil {
+				comparable = false
+			} else {
+				expected = *ref.AwardedPoints
+			}
+		}
+		if comparable && doc.Points != expected {
+			issues = append(issues, model.ReconcileIssue{
+				Kind:        model.ReconcilePointsMismatch,
+				ReferenceID: ref.ID,
+				MongoID:     ref.MongoID,
+				Postgres:    expected,
+				Mongo:       doc.Points,
+			})
+		}
+
+		if doc.Status != nil && *doc.Status != ref.Status {
+			issues = append(issues, model.ReconcileIssue{
+				Kind:        model.ReconcileStatusMismatch,
+				ReferenceID: ref.ID,
+				MongoID:     ref.MongoID,
+				Postgres:    ref.Status,
+				Mongo:       *doc.Status,
+			})
+		}
+	}
+
+	orphans := []string{}
+	for id := range docs {
+		if !referenced[id] {
+			orphans = append(orphans, id)
+		}
+	}
+	sort.Strings(orphans)
+
+	for _, id := range orphans {
+		doc := docs[id]
+		issues = append(issues, model.ReconcileIssue{
+			Kind:    model.ReconcileOrphanDocument,
+			MongoID: id,
+			Mongo:   doc.StudentID,
+		})
+	}
+
+	return issues, skipped
+}
+
+func (s *ReconciliationService) resolve(issue *model.ReconcileIssue, req model.ReconcileRequest, dryRun bool, actorUserID string) {
+	issue.Action = model.ReconcileActionNone
+
+	var err error
+	switch issue.Kind {
+	case model.ReconcileStudentMismatch, model.ReconcilePointsMismatch, model.ReconcileStatusMismatch:
+		if !req.Repair {
+			return
+		}
+		if dryRun {
+			issue.Action = model.ReconcileActionWouldRepair
+			return
+		}
+		err = s.repairDocument(*issue)
+		issue.Action = model.ReconcileActionRepaired
+
+	case model.ReconcileMissingDocument:
+		// dokumen yang gagal dibuat masih bisa dipulihkan dari payload outbox
+		if req.Repair {
+			var ok bool
+			ok, err = s.Repo.HasUnprocessedDraftEvent(issue.ReferenceID)
+			if err == nil && ok {
+				if dryRun {
+					issue.Action = model.ReconcileActionWouldRepair
+					return
+				}
+				_, err = s.Repo.RequeueDraftEvent(issue.ReferenceID)
+				issue.Action = model.ReconcileActionRepaired
+				break
+			}
+		}
+		if err != nil || !req.Quarantine {
+			break
+		}
+		// reference yang sudah diputuskan dosen tidak dihapus otomatis
+		if status, _ := issue.Postgres.(string); !model.CanTransition(model.ActionQuarantine, status) {
+			err = repository.ErrQuarantineNotAllowed
+			break
+		}
+		if dryRun {
+			issue.Action = model.ReconcileActionWouldQuarantine
+			return
+		}
+		err = s.Repo.QuarantineReference(issue.ReferenceID, actorUserID, "rekonsiliasi: dokumen MongoDB tidak ditemukan")
+		issue.Action = model.ReconcileActionQuarantined
+
+	case model.ReconcileOrphanDocument:
+		if !req.Quarantine {
+			return
+		}
+		if dryRun {
+			issue.Action = model.ReconcileActionWouldQuarantine
+			return
+		}
+		err = s.Repo.QuarantineDocument(issue.MongoID, "rekonsiliasi: tidak ada achievement_references")
+		issue.Action = model.ReconcileActionQuarantined
+	}
+
+	if err != nil {
+		issue.Action = model.ReconcileActionFailed
+		issue.Error = err.Error()
+	}
+}
+
+func (s *ReconciliationService) repairDocument(issue model.ReconcileIssue) error {
+	switch issue.Kind {
+	case model.ReconcilePointsMismatch:
+		return s.MongoRepo.UpdatePoints(issue.MongoID, issue.Postgres.(int))
+	case model.ReconcileStudentMismatch:
+		return s.MongoRepo.UpdateByID(issue.MongoID, map[string]interface{}{
+			"studentId": issue.Postgres,
+			"updatedAt": time.Now(),
+		})
+	default:
+		// status hanya disimpan di Postgres; field status di dokumen lama dibuang,
+		// bukan disalin, supaya tidak bisa berbeda lagi
+		return s.MongoRepo.UnsetFields(issue.MongoID, "status")
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindReconcileIssues(t *testing.T) {
+	awarded := 80
+	legacyStatus := "submitted"
+
+	refs := []model.ReconcileReference{
+		{ID: "r1", StudentID: "s1", MongoID: "m1", Status: model.StatusVerified, AwardedPoints: &awarded},
+		{ID: "r2", StudentID: "s1", MongoID: "m2", Status: model.StatusDraft},
+		{ID: "r3", StudentID: "s2", MongoID: "m3", Status: model.StatusVerified},
+		{ID: "r4", StudentID: "s2", MongoID: "m4", Status: model.StatusDraft, PendingOutbox: true},
+		{ID: "r5", StudentID: "s3", MongoID: "m5", Status: model.StatusRejected},
+	}
+	docs := map[string]model.ReconcileDocument{
+		"m1": {MongoID: "m1", StudentID: "s1", Points: 50},
+		"m2": {MongoID: "m2", StudentID: "s9", Points: 0, Status: &legacyStatus},
+		// verified lama tanpa awarded_points: poin tidak dibandingkan
+		"m3": {MongoID: "m3", StudentID: "s2", Points: 40},
+		"m9": {MongoID: "m9", StudentID: "s1"},
+	}
+
+	issues, skipped := findReconcileIssues(refs, docs)
+
+	assert.Equal(t, 1, skipped)
+
+	kinds := []string{}
+	for _, issue := range issues {
+		kinds = append(kinds, issue.Kind+":"+issue.ReferenceID+issue.MongoID)
+	}
+	assert.Equal(t, []string{
+		model.ReconcilePointsMismatch + ":r1m1",
+		model.ReconcileStudentMismatch + ":r2m2",
+		model.ReconcileStatusMismatch + ":r2m2",
+		model.ReconcileMissingDocument + ":r5m5",
+		model.ReconcileOrphanDocument + ":m9",
+	}, kinds)
+	assert.Equal(t, 80, issues[0].Postgres)
+	assert.Equal(t, 50, issues[0].Mongo)
+}
+
+func TestResolve_DryRunDoesNotWrite(t *testing.T) {
+	// repository nil: dry run tidak boleh menyentuh store apa pun
+	s := &ReconciliationService{}
+	req := model.ReconcileRequest{Repair: true, Quarantine: true}
+
+	mismatch := model.ReconcileIssue{Kind: model.ReconcilePointsMismatch, MongoID: "m1", Postgres: 80}
+	s.resolve(&mismatch, req, true, "")
+	assert.Equal(t, model.ReconcileActionWouldRepair, mismatch.Action)
+
+	orphan := model.ReconcileIssue{Kind: model.ReconcileOrphanDocument, MongoID: "m9"}
+	s.resolve(&orphan, req, true, "")
+	assert.Equal(t, model.ReconcileActionWouldQuarantine, orphan.Action)
+
+	orphan = model.ReconcileIssue{Kind: model.ReconcileOrphanDocument, MongoID: "m9"}
+	s.resolve(&orphan, model.ReconcileRequest{Repair: true}, false, "")
+	assert.Equal(t, model.ReconcileActionNone, orphan.Action)
+}
+
+func TestResolve_MissingDocumentRequeuesDraftEvent(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs("r1", model.OutboxAchievementDraftCreated).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(`UPDATE outbox_events[\s\S]+SET attempts = 0`).
+		WithArgs("r1", model.OutboxAchievementDraftCreated).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s := &ReconciliationService{Repo: repository.NewReconciliationRepository(db, nil)}
+	issue := model.ReconcileIssue{Kind: model.ReconcileMissingDocument, ReferenceID: "r1", MongoID: "m1"}
+	s.resolve(&issue, model.ReconcileRequest{Repair: true, Quarantine: true}, false, "u1")
+
+	assert.Equal(t, model.ReconcileActionRepaired, issue.Action)
+	assert.Empty(t, issue.Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolve_QuarantineOnlyUndecidedReferences(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	s := &ReconciliationService{Repo: repository.NewReconciliationRepository(db, nil)}
+	req := model.ReconcileRequest{Quarantine: true}
+
+	// sudah diverifikasi dosen: tidak boleh dihapus otomatis
+	verified := model.ReconcileIssue{Kind: model.ReconcileMissingDocument, ReferenceID: "r1", MongoID: "m1", Postgres: model.StatusVerified}
+	s.resolve(&verified, req, false, "u1")
+	assert.Equal(t, model.ReconcileActionFailed, verified.Action)
+	assert.Equal(t, repository.ErrQuarantineNotAllowed.Error(), verified.Error)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM achievement_references WHERE id = \$1 FOR UPDATE`).
+		WithArgs("r2").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StatusSubmitted))
+	mock.ExpectExec(`UPDATE achievement_references SET status = \$2`).
+		WithArgs("r2", model.StatusDeleted, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE achievement_submissions`).
+		WithArgs("r2", "withdrawn", "u1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO achievement_status_history`).
+		WithArgs("r2", model.StatusSubmitted, model.StatusDeleted, "u1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	submitted := model.ReconcileIssue{Kind: model.ReconcileMissingDocument, ReferenceID: "r2", MongoID: "m2", Postgres: model.StatusSubmitted}
+	s.resolve(&submitted, req, false, "u1")
+	assert.Equal(t, model.ReconcileActionQuarantined, submitted.Action)
+	assert.Empty(t, submitted.Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -13,11 +13,12 @@ import (
 )
 
 type ReportService struct {
-	Repo *repository.ReportRepository
+	Repo     *repository.ReportRepository
+	TypeRepo *repository.AchievementTypeRepository
 }
 
-func NewReportService(repo *repository.ReportRepository) *ReportService {
-	return &ReportService{Repo: repo}
+func NewReportService(repo *repository.ReportRepository, typeRepo *repository.AchievementTypeRepository) *ReportService {
+	return &ReportService{Repo: repo, TypeRepo: typeRepo}
 }
 
 // Statistics godoc
@@ -32,7 +33,7 @@ func (s *ReportService) Statistics(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
 	roleID := c.Locals("role_id").(string)
 
-	ids, err := s.Repo.GetAchievementReferencesByUser(userID, roleID)
+	ids, err := s.Repo.GetAchievementReferencesByUser(userID, roleID, orgScope(c))
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "failed to fetch references"})
 	}
@@ -78,6 +79,14 @@ func (s *ReportService) Statistics(c *fiber.Ctx) error {
 		bson.M{"$limit": 5},
 	}
 
+	perStudentPipeline := bson.A{
+		bson.M{"$match": bson.M{"_id": bson.M{"$in": objIDs}}},
+		bson.M{"$group": bson.M{
+			"_id":   "$studentId",
+			"total": bson.M{"$sum": 1},
+		}},
+	}
+
 	levelPipeline := bson.A{
 		bson.M{"$match": bson.M{"_id": bson.M{"$in": objIDs}}},
 		bson.M{"$group": bson.M{
@@ -90,6 +99,7 @@ func (s *ReportService) Statistics(c *fiber.Ctx) error {
 	periodAgg, _ := s.Repo.Aggregate(ctx, periodPipeline)
 	topAgg, _ := s.Repo.Aggregate(ctx, topPipeline)
 	levelAgg, _ := s.Repo.Aggregate(ctx, levelPipeline)
+	perStudentAgg, _ := s.Repo.Aggregate(ctx, perStudentPipeline)
 
 	// =====================
 	// BUILD RESPONSE
@@ -97,11 +107,22 @@ func (s *ReportService) Statistics(c *fiber.Ctx) error {
 
 	resp := model.StatisticsResponse{}
 
+	// nama jenis prestasi dari katalog, code yang tidak dikenal ditampilkan apa adanya
+	typeNames, err := s.TypeRepo.GetNames()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "failed to fetch achievement types"})
+	}
+	typeNames["unknown"] = "Tidak Diketahui"
+
 	for _, t := range typeAgg {
 		code := t["_id"].(string)
+		name, ok := typeNames[code]
+		if !ok {
+			name = code
+		}
 		resp.TotalPerType = append(resp.TotalPerType, model.StatisticItem{
 			Code:  code,
-			Name:  model.AchievementTypeMap[code],
+			Name:  name,
 			Total: int(t["total"].(int32)),
 		})
 	}
@@ -138,6 +159,38 @@ func (s *ReportService) Statistics(c *fiber.Ctx) error {
 		})
 	}
 
+	// total per program studi (dari profil akademik di Postgres)
+	allStudentIDs := []string{}
+	for _, t := range perStudentAgg {
+		allStudentIDs = append(allStudentIDs, t["_id"].(string))
+	}
+
+	programMap, _ := s.Repo.GetStudyProgramsByStudentIDs(allStudentIDs)
+	programTotals := map[string]int{}
+	programOrder := []string{}
+	for _, t := range perStudentAgg {
+		program := programMap[t["_id"].(string)]
+		if program == "" {
+			program = "unknown"
+		}
+		if _, ok := programTotals[program]; !ok {
+			programOrder = append(programOrder, program)
+		}
+		programTotals[program] += int(t["total"].(int32))
+	}
+
+	for _, program := range programOrder {
+		name := program
+		if program == "unknown" {
+			name = "Tidak Diketahui"
+		}
+		resp.TotalPerProgram = append(resp.TotalPerProgram, model.StatisticItem{
+			Code:  program,
+			Name:  name,
+			Total: programTotals[program],
+		})
+	}
+
 	return c.JSON(resp)
 }
 
@@ -147,12 +200,20 @@ func (s *ReportService) Statistics(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Student ID"
 // @Success 200 {array} model.StudentAchievementReportResponse
+// @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /api/v1/reports/student/{id} [get]
 func (s *ReportService) StudentReport(c *fiber.Ctx) error {
 	studentID := c.Params("id")
 	ctx := context.Background()
 
+	if scope := orgScope(c); scope != nil {
+		unitID, err := s.Repo.GetStudentUnit(studentID)
+		if err != nil || !inScope(scope, unitID) {
+			return c.Status(404).JSON(fiber.Map{"error": "student not found"})
+		}
+	}
+
 	pipeline := mongo.Pipeline{
 		bson.D{{Key: "$match", Value: bson.M{"studentId": studentID}}},
 	}
@@ -1,9 +1,17 @@
 package service
 
 import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"uas-prestasi/app/model"
 	"uas-prestasi/app/repository"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type StudentService struct {
@@ -15,21 +23,56 @@ func NewStudentService(sr *repository.StudentRepository, lr *repository.Lecturer
 	return &StudentService{sr, lr}
 }
 
+// id yang bukan UUID dianggap tidak ditemukan, jangan diteruskan ke Postgres
+func isUUID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
 // GetStudents godoc
 // @Summary Get all students
-// @Description Menampilkan daftar seluruh mahasiswa
+// @Description Menampilkan daftar seluruh mahasiswa beserta profil akademik, bisa difilter
 // @Tags Student
 // @Produce json
-// @Success 200 {array} model.Student
+// @Param nim query string false "Filter NIM"
+// @Param study_program_id query string false "Filter id program studi"
+// @Param study_program query string false "Filter program studi"
+// @Param faculty query string false "Filter fakultas"
+// @Param entry_year query int false "Filter angkatan"
+// @Param semester query int false "Filter semester"
+// @Param academic_status query string false "Filter status akademik (active, leave, graduated, dropped_out, inactive)"
+// @Success 200 {object} model.StudentListResponse
+// @Failure 400 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/students [get]
 func (s *StudentService) GetStudents(c *fiber.Ctx) error {
-	data, err := s.StudentRepo.GetAll()
+	filter := model.StudentFilter{
+		NIM:            c.Query("nim"),
+		StudyProgram:   c.Query("study_program"),
+		Faculty:        c.Query("faculty"),
+		EntryYear:      c.QueryInt("entry_year"),
+		Semester:       c.QueryInt("semester"),
+		AcademicStatus: c.Query("academic_status"),
+		StudyProgramID: c.Query("study_program_id"),
+		Scope:          orgScope(c),
+	}
+
+	if filter.AcademicStatus != "" {
+		if _, ok := model.AcademicStatusMap[filter.AcademicStatus]; !ok {
+			return c.Status(400).JSON(fiber.Map{"error": "academic_status tidak valid"})
+		}
+	}
+
+	data, err := s.StudentRepo.GetAll(filter)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
-	return c.JSON(data)
+
+	return c.JSON(model.StudentListResponse{
+		Status: "success",
+		Data:   data,
+	})
 }
 
 // GetStudent godoc
@@ -38,7 +81,7 @@ func (s *StudentService) GetStudents(c *fiber.Ctx) error {
 // @Tags Student
 // @Produce json
 // @Param id path string true "Student ID"
-// @Success 200 {object} model.Student
+// @Success 200 {object} model.StudentDetailResponse
 // @Failure 404 {object} model.MessageResponse
 // @Security BearerAuth
 // @Router /api/v1/students/{id} [get]
@@ -46,10 +89,88 @@ func (s *StudentService) GetStudent(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	data, err := s.StudentRepo.GetByID(id)
-	if err != nil {
+	if err != nil || !inScope(orgScope(c), data.StudyProgramID) {
 		return c.Status(404).JSON(fiber.Map{"message": "student not found"})
 	}
-	return c.JSON(data)
+
+	return c.JSON(model.StudentDetailResponse{
+		Status: "success",
+		Data:   *data,
+	})
+}
+
+// UpdateStudent godoc
+// @Summary Update student academic profile
+// @Description Mengubah profil akademik mahasiswa (NIM, prodi, fakultas, angkatan, semester, status)
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Param id path string true "Student ID"
+// @Param request body model.UpdateStudentRequest true "Profil akademik"
+// @Success 200 {object} model.StudentDetailResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/students/{id} [put]
+func (s *StudentService) UpdateStudent(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req model.UpdateStudentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid payload"})
+	}
+
+	if req.AcademicStatus == "" {
+		req.AcademicStatus = model.AcademicStatusActive
+	}
+
+	if msg := validateStudentProfile(req); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	current, err := s.StudentRepo.GetByID(id)
+	if err != nil || !inScope(orgScope(c), current.StudyProgramID) {
+		return c.Status(404).JSON(fiber.Map{"error": "student not found"})
+	}
+
+	err = s.StudentRepo.UpdateProfile(id, req)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "student not found"})
+	}
+	if err == repository.ErrDuplicateNIM {
+		return c.Status(409).JSON(fiber.Map{"error": "nim sudah terdaftar"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal update profil mahasiswa"})
+	}
+
+	data, err := s.StudentRepo.GetByID(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "student not found"})
+	}
+
+	return c.JSON(model.StudentDetailResponse{
+		Status: "success",
+		Data:   *data,
+	})
+}
+
+func validateStudentProfile(req model.UpdateStudentRequest) string {
+	if req.NIM == "" {
+		return "nim wajib diisi"
+	}
+	if _, ok := model.AcademicStatusMap[req.AcademicStatus]; !ok {
+		return "academic_status tidak valid"
+	}
+	if req.EntryYear < 1900 || req.EntryYear > time.Now().Year()+1 {
+		return "entry_year tidak valid"
+	}
+	if req.Semester < 1 || req.Semester > 14 {
+		return "semester harus antara 1 dan 14"
+	}
+	return ""
 }
 
 // GetStudentAchievements godoc
@@ -59,12 +180,20 @@ func (s *StudentService) GetStudent(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Student ID"
 // @Success 200 {array} model.StudentAchievementResponse
+// @Failure 404 {object} model.MessageResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/students/{id}/achievements [get]
 func (s *StudentService) GetStudentAchievements(c *fiber.Ctx) error {
 	id := c.Params("id")
 
+	if scope := orgScope(c); scope != nil {
+		student, err := s.StudentRepo.GetByID(id)
+		if err != nil || !inScope(scope, student.StudyProgramID) {
+			return c.Status(404).JSON(fiber.Map{"message": "student not found"})
+		}
+	}
+
 	data, err := s.StudentRepo.GetAchievements(id)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -75,7 +204,10 @@ func (s *StudentService) GetStudentAchievements(c *fiber.Ctx) error {
 
 // AssignAdvisor godoc
 // @Summary Assign academic advisor
-// @Description Menentukan dosen wali untuk mahasiswa
+// @Description Menentukan dosen wali untuk mahasiswa. advisor_id null / kosong untuk melepas dosen wali.
+// Dosen harus ada dan aktif. Setiap pergantian dicatat di riwayat dosen wali.
+// Prestasi yang masih submitted ikut pindah ke antrean verifikasi dosen wali baru,
+// karena itu dosen wali tidak bisa dilepas selama masih ada prestasi submitted.
 // @Tags Student
 // @Accept json
 // @Produce json
@@ -83,61 +215,692 @@ func (s *StudentService) GetStudentAchievements(c *fiber.Ctx) error {
 // @Param request body model.UpdateAdvisorRequest true "Advisor payload"
 // @Success 200 {object} model.AssignAdvisorResponse
 // @Failure 400 {object} model.MessageResponse
+// @Failure 404 {object} model.MessageResponse
+// @Failure 409 {object} model.MessageResponse
 // @Failure 500 {object} model.MessageResponse
 // @Security BearerAuth
 // @Router /api/v1/students/{id}/advisor [put]
 func (s *StudentService) AssignAdvisor(c *fiber.Ctx) error {
 	studentID := c.Params("id")
+	actorID, _ := c.Locals("user_id").(string)
 
-	var body struct {
-		AdvisorID string `json:"advisor_id"`
-	}
+	var body model.UpdateAdvisorRequest
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(400).JSON(fiber.Map{"message": "invalid payload"})
 	}
 
-	err := s.StudentRepo.UpdateAdvisor(studentID, body.AdvisorID)
+	if body.AdvisorID != nil && *body.AdvisorID == "" {
+		body.AdvisorID = nil
+	}
+
+	scope := orgScope(c)
+	if scope != nil {
+		student, err := s.StudentRepo.GetByID(studentID)
+		if err != nil || !inScope(scope, student.StudyProgramID) {
+			return c.Status(404).JSON(fiber.Map{"message": "student not found"})
+		}
+	}
+
+	if body.AdvisorID != nil {
+		if !isUUID(*body.AdvisorID) {
+			return c.Status(400).JSON(fiber.Map{"message": "dosen tidak ditemukan"})
+		}
+
+		lecturer, err := s.LecturerRepo.GetByID(*body.AdvisorID)
+		if err == sql.ErrNoRows {
+			return c.Status(400).JSON(fiber.Map{"message": "dosen tidak ditemukan"})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "gagal memvalidasi dosen"})
+		}
+		if !lecturer.IsActive {
+			return c.Status(400).JSON(fiber.Map{"message": "dosen sudah tidak aktif"})
+		}
+		if !inScope(scope, lecturer.OrgUnitID) {
+			return c.Status(400).JSON(fiber.Map{"message": "dosen di luar unit Anda"})
+		}
+	}
+
+	previous, pending, err := s.StudentRepo.UpdateAdvisor(studentID, body.AdvisorID, actorID)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"message": "student not found"})
+	}
+	if err == repository.ErrPendingSubmissions {
+		return c.Status(409).JSON(fiber.Map{"message": "mahasiswa masih memiliki prestasi submitted, pindahkan ke dosen wali lain"})
+	}
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"message": "gagal assign advisor"})
 	}
 
-	return c.JSON(fiber.Map{"message": "advisor assigned", "advisor_id": body.AdvisorID})
+	message := "advisor assigned"
+	if body.AdvisorID == nil {
+		message = "advisor unassigned"
+	}
+
+	return c.JSON(model.AssignAdvisorResponse{
+		Message:           message,
+		AdvisorID:         body.AdvisorID,
+		PreviousAdvisorID: previous,
+		ReassignedPending: pending,
+	})
+}
+
+// GetAdvisorHistory godoc
+// @Summary Get advisor history
+// @Description Menampilkan riwayat dosen wali mahasiswa beserta periode bimbingan
+// @Tags Student
+// @Produce json
+// @Param id path string true "Student ID"
+// @Success 200 {object} model.AdvisorHistoryResponse
+// @Failure 404 {object} model.MessageResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/students/{id}/advisor-history [get]
+func (s *StudentService) GetAdvisorHistory(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if scope := orgScope(c); scope != nil {
+		student, err := s.StudentRepo.GetByID(id)
+		if err != nil || !inScope(scope, student.StudyProgramID) {
+			return c.Status(404).JSON(fiber.Map{"message": "student not found"})
+		}
+	}
+
+	data, err := s.StudentRepo.GetAdvisorHistory(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(model.AdvisorHistoryResponse{
+		Status: "success",
+		Data:   data,
+	})
 }
 
 // GetLecturers godoc
 // @Summary Get all lecturers
-// @Description Menampilkan daftar dosen
+// @Description Menampilkan daftar dosen beserta NIDN, jurusan, dan jumlah mahasiswa bimbingan
 // @Tags Lecturer
 // @Produce json
-// @Success 200 {array} model.AdviseeResponse
+// @Success 200 {object} model.LecturerListResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/lecturers [get]
 func (s *StudentService) GetLecturers(c *fiber.Ctx) error {
-	data, err := s.LecturerRepo.GetAll()
+	data, err := s.LecturerRepo.GetAll(orgScope(c))
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
-	return c.JSON(data)
+
+	return c.JSON(model.LecturerListResponse{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+// GetLecturer godoc
+// @Summary Get lecturer detail
+// @Description Menampilkan detail dosen
+// @Tags Lecturer
+// @Produce json
+// @Param id path string true "Lecturer ID"
+// @Success 200 {object} model.LecturerDetailResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/lecturers/{id} [get]
+func (s *StudentService) GetLecturer(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	data, err := s.LecturerRepo.GetByID(id)
+	if err != nil || !inScope(orgScope(c), data.OrgUnitID) {
+		return c.Status(404).JSON(fiber.Map{"error": "lecturer not found"})
+	}
+
+	return c.JSON(model.LecturerDetailResponse{
+		Status: "success",
+		Data:   *data,
+	})
+}
+
+// validasi unit dosen: harus jurusan/prodi aktif dan di dalam scope admin
+func (s *StudentService) checkLecturerUnit(c *fiber.Ctx, unitID *string) (string, int) {
+	scope := orgScope(c)
+	if unitID == nil {
+		if scope != nil {
+			return "org_unit_id wajib diisi", 400
+		}
+		return "", 0
+	}
+
+	ok, err := s.LecturerRepo.IsTeachingUnit(*unitID)
+	if err != nil {
+		return "gagal memvalidasi unit", 500
+	}
+	if !ok || !inScope(scope, unitID) {
+		return "org_unit_id harus jurusan atau program studi yang aktif", 400
+	}
+
+	return "", 0
+}
+
+// CreateLecturer godoc
+// @Summary Create lecturer
+// @Description Membuat profil dosen untuk user yang sudah ada
+// @Tags Lecturer
+// @Accept json
+// @Produce json
+// @Param request body model.CreateLecturerRequest true "Data dosen"
+// @Success 201 {object} model.LecturerDetailResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/lecturers [post]
+func (s *StudentService) CreateLecturer(c *fiber.Ctx) error {
+	var req model.CreateLecturerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid payload"})
+	}
+
+	if req.UserID == "" || req.NIDN == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "user_id dan nidn wajib diisi"})
+	}
+
+	if msg, code := s.checkLecturerUnit(c, req.OrgUnitID); msg != "" {
+		return c.Status(code).JSON(fiber.Map{"error": msg})
+	}
+
+	exists, err := s.LecturerRepo.UserProfileExists(req.UserID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal memvalidasi user"})
+	}
+	if exists {
+		return c.Status(409).JSON(fiber.Map{"error": "user sudah terdaftar sebagai dosen atau mahasiswa"})
+	}
+
+	id, err := s.LecturerRepo.Create(req)
+	if err == repository.ErrDuplicateNIDN {
+		return c.Status(409).JSON(fiber.Map{"error": "nidn sudah terdaftar"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal menyimpan dosen"})
+	}
+
+	data, err := s.LecturerRepo.GetByID(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil data dosen"})
+	}
+
+	return c.Status(201).JSON(model.LecturerDetailResponse{
+		Status: "success",
+		Data:   *data,
+	})
+}
+
+// UpdateLecturer godoc
+// @Summary Update lecturer
+// @Description Mengubah NIDN dan jurusan dosen
+// @Tags Lecturer
+// @Accept json
+// @Produce json
+// @Param id path string true "Lecturer ID"
+// @Param request body model.UpdateLecturerRequest true "Data dosen"
+// @Success 200 {object} model.LecturerDetailResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/lecturers/{id} [put]
+func (s *StudentService) UpdateLecturer(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	current, err := s.LecturerRepo.GetByID(id)
+	if err != nil || !inScope(orgScope(c), current.OrgUnitID) {
+		return c.Status(404).JSON(fiber.Map{"error": "lecturer not found"})
+	}
+
+	var req model.UpdateLecturerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid payload"})
+	}
+
+	if req.NIDN == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "nidn wajib diisi"})
+	}
+
+	if msg, code := s.checkLecturerUnit(c, req.OrgUnitID); msg != "" {
+		return c.Status(code).JSON(fiber.Map{"error": msg})
+	}
+
+	if err := s.LecturerRepo.Update(id, req); err != nil {
+		if err == repository.ErrDuplicateNIDN {
+			return c.Status(409).JSON(fiber.Map{"error": "nidn sudah terdaftar"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "gagal update dosen"})
+	}
+
+	data, err := s.LecturerRepo.GetByID(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "lecturer not found"})
+	}
+
+	return c.JSON(model.LecturerDetailResponse{
+		Status: "success",
+		Data:   *data,
+	})
+}
+
+// DeactivateLecturer godoc
+// @Summary Deactivate lecturer
+// @Description Menonaktifkan dosen. Mahasiswa bimbingan harus dipindahkan terlebih dahulu
+// @Tags Lecturer
+// @Produce json
+// @Param id path string true "Lecturer ID"
+// @Success 200 {object} model.MessageResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/lecturers/{id} [delete]
+func (s *StudentService) DeactivateLecturer(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	current, err := s.LecturerRepo.GetByID(id)
+	if err != nil || !inScope(orgScope(c), current.OrgUnitID) {
+		return c.Status(404).JSON(fiber.Map{"error": "lecturer not found"})
+	}
+
+	if current.AdviseeCount > 0 {
+		return c.Status(409).JSON(fiber.Map{
+			"error": fmt.Sprintf("dosen masih memiliki %d mahasiswa bimbingan", current.AdviseeCount),
+		})
+	}
+
+	if err := s.LecturerRepo.SetActive(id, false); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal menonaktifkan dosen"})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "dosen dinonaktifkan",
+	})
 }
 
 // GetAdvisees godoc
 // @Summary Get advisees
-// @Description Menampilkan daftar mahasiswa bimbingan dosen
+// @Description Menampilkan daftar mahasiswa bimbingan dosen beserta jumlah prestasi pending dan terverifikasi
 // @Tags Lecturer
 // @Produce json
 // @Param id path string true "Lecturer ID"
-// @Success 200 {array} model.AdviseeResponse
+// @Success 200 {object} model.AdviseeListResponse
+// @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/lecturers/{id}/advisees [get]
 func (s *StudentService) GetAdvisees(c *fiber.Ctx) error {
 	id := c.Params("id")
 
+	if scope := orgScope(c); scope != nil {
+		lecturer, err := s.LecturerRepo.GetByID(id)
+		if err != nil || !inScope(scope, lecturer.OrgUnitID) {
+			return c.Status(404).JSON(fiber.Map{"error": "lecturer not found"})
+		}
+	}
+
 	data, err := s.LecturerRepo.GetAdvisees(id)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.JSON(data)
+	return c.JSON(model.AdviseeListResponse{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+const defaultMaxAdvisees = 30
+
+// batas mahasiswa bimbingan per dosen, bisa diatur lewat ADVISOR_MAX_ADVISEES
+func maxAdviseesFromEnv() int {
+	if v, err := strconv.Atoi(os.Getenv("ADVISOR_MAX_ADVISEES")); err == nil && v > 0 {
+		return v
+	}
+	return defaultMaxAdvisees
+}
+
+// planAdvisorDistribution membagi mahasiswa ke dosen dengan beban paling sedikit
+// tanpa melewati maxAdvisees. Mahasiswa yang tidak kebagian dikembalikan di leftover.
+func planAdvisorDistribution(students []model.Student, lecturers []model.Lecturer, maxAdvisees int) (
+	assigned []model.AdvisorAssignmentResult,
+	leftover []model.AdvisorAssignmentResult,
+	loads []model.LecturerLoad,
+) {
+	assigned = []model.AdvisorAssignmentResult{}
+	leftover = []model.AdvisorAssignmentResult{}
+	loads = make([]model.LecturerLoad, len(lecturers))
+
+	for i, l := range lecturers {
+		loads[i] = model.LecturerLoad{
+			LecturerID:   l.ID,
+			LecturerName: l.Name,
+			Before:       l.AdviseeCount,
+			After:        l.AdviseeCount,
+		}
+	}
+
+	for _, st := range students {
+		pick := -1
+		for i := range loads {
+			if loads[i].After >= maxAdvisees {
+				continue
+			}
+			if pick == -1 || loads[i].After < loads[pick].After {
+				pick = i
+			}
+		}
+
+		result := model.AdvisorAssignmentResult{
+			StudentID:   st.ID,
+			StudentName: st.FullName,
+			NIM:         st.NIM,
+		}
+
+		if pick == -1 {
+			result.Error = "semua dosen sudah mencapai batas bimbingan"
+			leftover = append(leftover, result)
+			continue
+		}
+
+		loads[pick].After++
+		lecturerID := loads[pick].LecturerID
+		result.LecturerID = &lecturerID
+		result.LecturerName = loads[pick].LecturerName
+		assigned = append(assigned, result)
+	}
+
+	return assigned, leftover, loads
+}
+
+// BulkAssignAdvisor godoc
+// @Summary Bulk assign academic advisor
+// @Description Menentukan dosen wali untuk banyak mahasiswa sekaligus.
+// commit=false (default) hanya menampilkan preview & validasi. Dengan commit=true semua
+// assignment dijalankan dalam satu transaksi, jika ada satu yang tidak valid tidak ada yang disimpan.
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Param request body model.BulkAssignAdvisorRequest true "Daftar pasangan mahasiswa → dosen"
+// @Success 200 {object} model.BulkAssignAdvisorResponse
+// @Failure 400 {object} model.MessageResponse
+// @Failure 409 {object} model.MessageResponse
+// @Failure 422 {object} model.BulkAssignAdvisorResponse
+// @Failure 500 {object} model.MessageResponse
+// @Security BearerAuth
+// @Router /api/v1/students/advisors/bulk [post]
+func (s *StudentService) BulkAssignAdvisor(c *fiber.Ctx) error {
+	actorID, _ := c.Locals("user_id").(string)
+	scope := orgScope(c)
+
+	var req model.BulkAssignAdvisorRequest
+	if err := c.BodyParser(&req); err != nil || len(req.Assignments) == 0 {
+		return c.Status(400).JSON(fiber.Map{"message": "assignments wajib diisi"})
+	}
+
+	if len(req.Assignments) > 1000 {
+		return c.Status(400).JSON(fiber.Map{"message": "maksimal 1000 assignment per request"})
+	}
+
+	// id yang bukan UUID tidak dikirim ke Postgres, dilaporkan per baris
+	studentIDs := []string{}
+	lecturerIDs := []string{}
+	for i, a := range req.Assignments {
+		if a.LecturerID != nil && *a.LecturerID == "" {
+			req.Assignments[i].LecturerID = nil
+		}
+		if isUUID(a.StudentID) {
+			studentIDs = append(studentIDs, a.StudentID)
+		}
+		if req.Assignments[i].LecturerID != nil && isUUID(*a.LecturerID) {
+			lecturerIDs = append(lecturerIDs, *a.LecturerID)
+		}
+	}
+
+	students, err := s.StudentRepo.GetByIDs(studentIDs)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "gagal mengambil data mahasiswa"})
+	}
+
+	lecturers, err := s.LecturerRepo.GetByIDs(lecturerIDs)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "gagal mengambil data dosen"})
+	}
+
+	results := []model.AdvisorAssignmentResult{}
+	seen := map[string]bool{}
+	failed := 0
+
+	for _, a := range req.Assignments {
+		result := model.AdvisorAssignmentResult{
+			StudentID:  a.StudentID,
+			LecturerID: a.LecturerID,
+		}
+
+		st, ok := students[a.StudentID]
+		switch {
+		case !isUUID(a.StudentID):
+			result.Error = "student_id tidak valid"
+		case !ok || !inScope(scope, st.StudyProgramID):
+			result.Error = "mahasiswa tidak ditemukan"
+		case seen[a.StudentID]:
+			result.Error = "mahasiswa muncul lebih dari sekali"
+		}
+
+		if ok {
+			result.StudentName = st.FullName
+			result.NIM = st.NIM
+		}
+		seen[a.StudentID] = true
+
+		if result.Error == "" && a.LecturerID != nil {
+			l, ok := lecturers[*a.LecturerID]
+			switch {
+			case !isUUID(*a.LecturerID):
+				result.Error = "lecturer_id tidak valid"
+			case !ok || !inScope(scope, l.OrgUnitID):
+				result.Error = "dosen tidak ditemukan"
+			case !l.IsActive:
+				result.Error = "dosen sudah tidak aktif"
+			default:
+				result.LecturerName = l.Name
+			}
+		}
+
+		if result.Error != "" {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	resp := model.BulkAssignAdvisorResponse{
+		Status: "success",
+		Failed: failed,
+		Data:   results,
+	}
+
+	if failed > 0 {
+		resp.Status = "error"
+		return c.Status(422).JSON(resp)
+	}
+
+	if req.Commit {
+		err := s.StudentRepo.BulkUpdateAdvisor(req.Assignments, actorID)
+		if err == repository.ErrPendingSubmissions {
+			return c.Status(409).JSON(fiber.Map{"message": "ada mahasiswa yang masih memiliki prestasi submitted, pindahkan ke dosen wali lain"})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "gagal menyimpan assignment"})
+		}
+		resp.Committed = true
+	}
+
+	return c.JSON(resp)
+}
+
+// AutoAssignAdvisor godoc
+// @Summary Auto assign academic advisor
+// @Description Membagi mahasiswa aktif tanpa dosen wali di satu program studi secara merata ke dosen aktif
+// prodi tsb (atau jurusan induknya), tanpa melewati max_advisees (default ADVISOR_MAX_ADVISEES / 30).
+// commit=false (default) hanya menampilkan rencana pembagian.
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Param request body model.AutoAssignAdvisorRequest true "Program studi & batas bimbingan"
+// @Success 200 {object} model.AutoAssignAdvisorResponse
+// @Failure 400 {object} model.MessageResponse
+// @Failure 404 {object} model.MessageResponse
+// @Failure 500 {object} model.MessageResponse
+// @Security BearerAuth
+// @Router /api/v1/students/advisors/auto [post]
+func (s *StudentService) AutoAssignAdvisor(c *fiber.Ctx) error {
+	actorID, _ := c.Locals("user_id").(string)
+
+	var req model.AutoAssignAdvisorRequest
+	if err := c.BodyParser(&req); err != nil || req.StudyProgramID == "" {
+		return c.Status(400).JSON(fiber.Map{"message": "study_program_id wajib diisi"})
+	}
+
+	if !inScope(orgScope(c), &req.StudyProgramID) {
+		return c.Status(404).JSON(fiber.Map{"message": "program studi tidak ditemukan"})
+	}
+
+	if req.MaxAdvisees <= 0 {
+		req.MaxAdvisees = maxAdviseesFromEnv()
+	}
+
+	students, err := s.StudentRepo.GetUnassigned(req.StudyProgramID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "gagal mengambil data mahasiswa"})
+	}
+
+	lecturers, err := s.LecturerRepo.GetActiveForStudyProgram(req.StudyProgramID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"message": "gagal mengambil data dosen"})
+	}
+
+	assigned, leftover, loads := planAdvisorDistribution(students, lecturers, req.MaxAdvisees)
+
+	resp := model.AutoAssignAdvisorResponse{
+		Status:       "success",
+		MaxAdvisees:  req.MaxAdvisees,
+		Data:         assigned,
+		Unassigned:   leftover,
+		LecturerLoad: loads,
+	}
+
+	if req.Commit && len(assigned) > 0 {
+		assignments := make([]model.AdvisorAssignment, len(assigned))
+		for i, a := range assigned {
+			assignments[i] = model.AdvisorAssignment{StudentID: a.StudentID, LecturerID: a.LecturerID}
+		}
+
+		if err := s.StudentRepo.BulkUpdateAdvisor(assignments, actorID); err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "gagal menyimpan assignment"})
+		}
+		resp.Committed = true
+	}
+
+	return c.JSON(resp)
+}
+
+// semesterStart mengembalikan awal semester berjalan:
+// semester ganjil mulai 1 Agustus, semester genap mulai 1 Februari
+func semesterStart(now time.Time) time.Time {
+	year := now.Year()
+	switch {
+	case now.Month() >= time.August:
+		return time.Date(year, time.August, 1, 0, 0, 0, 0, now.Location())
+	case now.Month() == time.January:
+		return time.Date(year-1, time.August, 1, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Date(year, time.February, 1, 0, 0, 0, 0, now.Location())
+	}
+}
+
+// GetMyDashboard godoc
+// @Summary Advisor dashboard
+// @Description Ringkasan untuk dosen wali login: mahasiswa bimbingan dan jumlah prestasi menunggu verifikasi,
+// umur pengajuan tertua yang belum diproses, verifikasi/penolakan terakhir, dan mahasiswa
+// bimbingan yang belum punya prestasi di semester ini
+// @Tags Lecturer
+// @Produce json
+// @Success 200 {object} model.LecturerDashboardResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/lecturers/me/dashboard [get]
+func (s *StudentService) GetMyDashboard(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	lecturer, err := s.LecturerRepo.GetByUserID(userID)
+	if err == sql.ErrNoRows {
+		return c.Status(403).JSON(fiber.Map{"error": "user is not a lecturer"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil data dosen"})
+	}
+
+	advisees, err := s.LecturerRepo.GetAdvisees(lecturer.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil mahasiswa bimbingan"})
+	}
+
+	oldest, err := s.LecturerRepo.GetOldestPendingSubmission(lecturer.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil pengajuan"})
+	}
+
+	decisions, err := s.LecturerRepo.GetRecentDecisions(userID, 10)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil riwayat verifikasi"})
+	}
+
+	now := time.Now()
+	since := semesterStart(now)
+
+	inactiveIDs, err := s.LecturerRepo.GetAdviseesWithoutAchievementSince(lecturer.ID, since)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal mengambil aktivitas mahasiswa"})
+	}
+
+	inactiveSet := map[string]bool{}
+	for _, id := range inactiveIDs {
+		inactiveSet[id] = true
+	}
+
+	dashboard := model.LecturerDashboard{
+		Lecturer:                 *lecturer,
+		Advisees:                 advisees,
+		OldestPendingSubmittedAt: oldest,
+		RecentDecisions:          decisions,
+		SemesterStart:            since,
+		InactiveAdvisees:         []model.AdviseeResponse{},
+	}
+
+	for _, a := range advisees {
+		dashboard.TotalPending += a.PendingCount
+		if inactiveSet[a.ID] {
+			dashboard.InactiveAdvisees = append(dashboard.InactiveAdvisees, a)
+		}
+	}
+
+	if oldest != nil {
+		hours := int(now.Sub(*oldest).Hours())
+		dashboard.OldestPendingAgeHours = &hours
+	}
+
+	return c.JSON(model.LecturerDashboardResponse{
+		Status: "success",
+		Data:   dashboard,
+	})
 }
@@ -2,14 +2,17 @@ package service
 
 import (
 	"database/sql"
+	"encoding/json"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"uas-prestasi/app/model"
 	"uas-prestasi/app/repository"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,10 +23,10 @@ func TestGetStudents_Success(t *testing.T) {
 	defer db.Close()
 
 	// 2️⃣ mock query result
-	rows := sqlmock.NewRows([]string{"id", "user_id", "advisor_id"}).
-		AddRow("s1", "u1", nil)
+	rows := sqlmock.NewRows([]string{"id", "user_id", "full_name", "nim", "study_program_id", "study_program", "faculty", "entry_year", "semester", "academic_status", "advisor_id"}).
+		AddRow("s1", "u1", "John Doe", "434231023", nil, "Teknik Informatika", "Vokasi", 2023, 5, "active", nil)
 
-	mock.ExpectQuery(`SELECT id, user_id, advisor_id FROM students`).
+	mock.ExpectQuery(`FROM students s[\s\S]+ORDER BY s.nim`).
 		WillReturnRows(rows)
 
 	// 3️⃣ init repo & service
@@ -47,7 +50,7 @@ func TestGetStudent_NotFound(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, user_id, advisor_id FROM students WHERE id=\$1`).
+	mock.ExpectQuery(`FROM students s[\s\S]+WHERE s.id=\$1`).
 		WithArgs("s1").
 		WillReturnError(sql.ErrNoRows)
 
@@ -63,14 +66,68 @@ func TestGetStudent_NotFound(t *testing.T) {
 	assert.Equal(t, 404, resp.StatusCode)
 }
 
+func TestGetStudentAchievements_OutsideScope(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "full_name", "nim", "study_program_id", "study_program", "faculty", "entry_year", "semester", "academic_status", "advisor_id"}).
+		AddRow("s1", "u1", "John Doe", "434231023", "prodi-lain", "Teknik Sipil", "Teknik", 2023, 5, "active", nil)
+
+	mock.ExpectQuery(`FROM students s[\s\S]+WHERE s.id=\$1`).
+		WithArgs("s1").
+		WillReturnRows(rows)
+
+	service := NewStudentService(repository.NewStudentRepository(db), nil)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		// admin jurusan teknik elektro
+		c.Locals("org_scope", []string{"dep-te", "prodi-ti"})
+		return c.Next()
+	})
+	app.Get("/students/:id/achievements", service.GetStudentAchievements)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/students/s1/achievements", nil))
+
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateStudent_DuplicateNIM(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "full_name", "nim", "study_program_id", "study_program", "faculty", "entry_year", "semester", "academic_status", "advisor_id"}).
+		AddRow("s1", "u1", "John Doe", "434231023", nil, "Teknik Informatika", "Vokasi", 2023, 5, "active", nil)
+
+	mock.ExpectQuery(`FROM students s[\s\S]+WHERE s.id=\$1`).
+		WithArgs("s1").
+		WillReturnRows(rows)
+	mock.ExpectExec(`UPDATE students SET`).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "students_nim_key"})
+
+	service := NewStudentService(repository.NewStudentRepository(db), nil)
+
+	app := fiber.New()
+	app.Put("/students/:id", service.UpdateStudent)
+
+	body := `{"nim":"434231099","study_program":"Teknik Informatika","faculty":"Vokasi","entry_year":2023,"semester":5}`
+	req := httptest.NewRequest("PUT", "/students/s1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetStudent_Success(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
 
-	rows := sqlmock.NewRows([]string{"id", "user_id", "advisor_id"}).
-		AddRow("s1", "u1", nil)
+	rows := sqlmock.NewRows([]string{"id", "user_id", "full_name", "nim", "study_program_id", "study_program", "faculty", "entry_year", "semester", "academic_status", "advisor_id"}).
+		AddRow("s1", "u1", "John Doe", "434231023", nil, "Teknik Informatika", "Vokasi", 2023, 5, "active", nil)
 
-	mock.ExpectQuery(`SELECT id, user_id, advisor_id FROM students WHERE id=\$1`).
+	mock.ExpectQuery(`FROM students s[\s\S]+WHERE s.id=\$1`).
 		WithArgs("s1").
 		WillReturnRows(rows)
 
@@ -86,27 +143,207 @@ func TestGetStudent_Success(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 }
 
+func TestGetStudents_Filter(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "full_name", "nim", "study_program_id", "study_program", "faculty", "entry_year", "semester", "academic_status", "advisor_id"}).
+		AddRow("s1", "u1", "John Doe", "434231023", nil, "Teknik Informatika", "Vokasi", 2023, 5, "active", nil)
+
+	mock.ExpectQuery(`WHERE COALESCE\(sp.name, s.study_program\) ILIKE \$1 AND s.entry_year = \$2`).
+		WithArgs("Teknik Informatika", 2023).
+		WillReturnRows(rows)
+
+	studentRepo := repository.NewStudentRepository(db)
+	service := NewStudentService(studentRepo, nil)
+
+	app := fiber.New()
+	app.Get("/students", service.GetStudents)
+
+	req := httptest.NewRequest("GET", "/students?study_program=Teknik%20Informatika&entry_year=2023", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStudents_InvalidStatus(t *testing.T) {
+	service := NewStudentService(nil, nil)
+
+	app := fiber.New()
+	app.Get("/students", service.GetStudents)
+
+	req := httptest.NewRequest("GET", "/students?academic_status=alumni", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+const advisorID = "8b0c6a52-4f0e-4f7a-9f57-2d6f1a7c3e11"
+
 func TestAssignAdvisor_Success(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
 
+	mock.ExpectQuery(`FROM lecturers l .* WHERE l.id = \$1`).
+		WithArgs(advisorID).
+		WillReturnRows(sqlmock.NewRows(lecturerColumns).
+			AddRow(advisorID, "u2", "Budi Santoso", "dosenwali1@gmail.com", "0012345678", nil, "", true, 0))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT advisor_id FROM students WHERE id=\$1 FOR UPDATE`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"advisor_id"}).AddRow("l0"))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM achievement_references`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 	mock.ExpectExec(`UPDATE students SET advisor_id=\$1 WHERE id=\$2`).
-		WithArgs("l1", "s1").
+		WithArgs(advisorID, "s1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE advisor_history SET ended_at = NOW\(\)`).
+		WithArgs("s1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO advisor_history`).
+		WithArgs("s1", advisorID, "admin1").
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	studentRepo := repository.NewStudentRepository(db)
-	service := NewStudentService(studentRepo, nil)
+	lecturerRepo := repository.NewLecturerRepository(db)
+	service := NewStudentService(studentRepo, lecturerRepo)
 
 	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "admin1")
+		return c.Next()
+	})
 	app.Post("/students/:id/advisor", service.AssignAdvisor)
 
-	body := `{"advisor_id":"l1"}`
+	body := `{"advisor_id":"` + advisorID + `"}`
 	req := httptest.NewRequest("POST", "/students/s1/advisor", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, _ := app.Test(req)
 
 	assert.Equal(t, 200, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignAdvisor_InactiveLecturer(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM lecturers l .* WHERE l.id = \$1`).
+		WithArgs(advisorID).
+		WillReturnRows(sqlmock.NewRows(lecturerColumns).
+			AddRow(advisorID, "u2", "Budi Santoso", "dosenwali1@gmail.com", "0012345678", nil, "", false, 0))
+
+	service := NewStudentService(repository.NewStudentRepository(db), repository.NewLecturerRepository(db))
+
+	app := fiber.New()
+	app.Post("/students/:id/advisor", service.AssignAdvisor)
+
+	req := httptest.NewRequest("POST", "/students/s1/advisor", strings.NewReader(`{"advisor_id":"`+advisorID+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestAssignAdvisor_UnknownLecturer(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM lecturers l .* WHERE l.id = \$1`).
+		WithArgs(advisorID).
+		WillReturnError(sql.ErrNoRows)
+
+	service := NewStudentService(repository.NewStudentRepository(db), repository.NewLecturerRepository(db))
+
+	app := fiber.New()
+	app.Post("/students/:id/advisor", service.AssignAdvisor)
+
+	req := httptest.NewRequest("POST", "/students/s1/advisor", strings.NewReader(`{"advisor_id":"`+advisorID+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestAssignAdvisor_Unassign(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT advisor_id FROM students WHERE id=\$1 FOR UPDATE`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"advisor_id"}).AddRow(advisorID))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM achievement_references`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`UPDATE students SET advisor_id=\$1 WHERE id=\$2`).
+		WithArgs(nil, "s1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE advisor_history SET ended_at = NOW\(\)`).
+		WithArgs("s1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	service := NewStudentService(repository.NewStudentRepository(db), nil)
+
+	app := fiber.New()
+	app.Post("/students/:id/advisor", service.AssignAdvisor)
+
+	req := httptest.NewRequest("POST", "/students/s1/advisor", strings.NewReader(`{"advisor_id":""}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignAdvisor_MalformedLecturerID(t *testing.T) {
+	service := NewStudentService(nil, nil)
+
+	app := fiber.New()
+	app.Post("/students/:id/advisor", service.AssignAdvisor)
+
+	req := httptest.NewRequest("POST", "/students/s1/advisor", strings.NewReader(`{"advisor_id":"nope"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestAssignAdvisor_UnassignWithPendingSubmissions(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT advisor_id FROM students WHERE id=\$1 FOR UPDATE`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"advisor_id"}).AddRow(advisorID))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM achievement_references`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	service := NewStudentService(repository.NewStudentRepository(db), nil)
+
+	app := fiber.New()
+	app.Post("/students/:id/advisor", service.AssignAdvisor)
+
+	req := httptest.NewRequest("POST", "/students/s1/advisor", strings.NewReader(`{"advisor_id":null}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestAssignAdvisor_InvalidPayload(t *testing.T) {
@@ -127,3 +364,70 @@ func TestAssignAdvisor_InvalidPayload(t *testing.T) {
 	assert.Equal(t, 400, resp.StatusCode)
 }
 
+
+func TestBulkAssignAdvisor_MalformedIDIsRowError(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	studentID := "3f9a1c2e-7b44-4d0a-8e2f-5c6b7a8d9e01"
+
+	mock.ExpectQuery(`FROM students s[\s\S]+WHERE s.id = ANY\(\$1\)`).
+		WithArgs(`{"` + studentID + `","` + studentID + `"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "full_name", "nim", "study_program_id", "study_program", "faculty", "entry_year", "semester", "academic_status", "advisor_id"}).
+			AddRow(studentID, "u1", "John Doe", "434231023", nil, "Teknik Informatika", "Vokasi", 2023, 5, "active", nil))
+	mock.ExpectQuery(`FROM lecturers l[\s\S]+WHERE l.id = ANY\(\$1\)`).
+		WithArgs(`{"` + advisorID + `","` + advisorID + `"}`).
+		WillReturnRows(sqlmock.NewRows(lecturerColumns).
+			AddRow(advisorID, "u2", "Budi Santoso", "dosenwali1@gmail.com", "0012345678", nil, "", true, 0))
+
+	service := NewStudentService(repository.NewStudentRepository(db), repository.NewLecturerRepository(db))
+
+	app := fiber.New()
+	app.Post("/students/advisors/bulk", service.BulkAssignAdvisor)
+
+	body := `{"assignments":[
+		{"student_id":"` + studentID + `","lecturer_id":"` + advisorID + `"},
+		{"student_id":"bukan-uuid","lecturer_id":"` + advisorID + `"},
+		{"student_id":"` + studentID + `","lecturer_id":"l1"}
+	]}`
+	req := httptest.NewRequest("POST", "/students/advisors/bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+	assert.Equal(t, 422, resp.StatusCode)
+
+	var result model.BulkAssignAdvisorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, "", result.Data[0].Error)
+	assert.Equal(t, "student_id tidak valid", result.Data[1].Error)
+	assert.Equal(t, "mahasiswa muncul lebih dari sekali", result.Data[2].Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlanAdvisorDistribution_Balanced(t *testing.T) {
+	students := []model.Student{}
+	for _, id := range []string{"s1", "s2", "s3", "s4", "s5"} {
+		students = append(students, model.Student{ID: id})
+	}
+
+	lecturers := []model.Lecturer{
+		{ID: "l1", Name: "Dosen A", AdviseeCount: 2},
+		{ID: "l2", Name: "Dosen B", AdviseeCount: 0},
+		{ID: "l3", Name: "Dosen C", AdviseeCount: 3},
+	}
+
+	assigned, leftover, loads := planAdvisorDistribution(students, lecturers, 3)
+
+	// kuota tersisa: l1 = 1, l2 = 3, l3 = 0 → hanya 4 yang kebagian
+	assert.Len(t, assigned, 4)
+	assert.Len(t, leftover, 1)
+	assert.Equal(t, "s5", leftover[0].StudentID)
+
+	assert.Equal(t, 3, loads[0].After)
+	assert.Equal(t, 3, loads[1].After)
+	assert.Equal(t, 3, loads[2].After)
+
+	// mahasiswa pertama ke dosen dengan beban paling sedikit
+	assert.Equal(t, "l2", *assigned[0].LecturerID)
+}
@@ -1,10 +1,16 @@
 package service
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"uas-prestasi/app/model"
 	"uas-prestasi/app/repository"
+	"uas-prestasi/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type UserService struct {
@@ -15,6 +21,38 @@ func NewUserService(repo *repository.UserRepository) *UserService {
 	return &UserService{Repo: repo}
 }
 
+// admin unit hanya boleh mengelola user di dalam unitnya
+func (s *UserService) inUserScope(c *fiber.Ctx, id string) bool {
+	scope := orgScope(c)
+	if scope == nil {
+		return true
+	}
+
+	ok, err := s.Repo.IsInScope(id, scope)
+	return err == nil && ok
+}
+
+// adminGrantUnit: admin unit yang memberi role Admin selalu menempatkan user
+// tersebut di unit admin itu sendiri, supaya tidak ada admin baru tanpa batas
+// atau dengan scope lebih luas. nil berarti unit user tidak diubah.
+func (s *UserService) adminGrantUnit(c *fiber.Ctx, roleID string) (*string, error) {
+	scope := orgScope(c)
+	if scope == nil {
+		return nil, nil
+	}
+	if len(scope) == 0 {
+		return nil, errNoAdminUnit
+	}
+
+	isAdmin, err := s.Repo.IsAdminRole(roleID)
+	if err != nil || !isAdmin {
+		return nil, err
+	}
+	return &scope[0], nil
+}
+
+var errNoAdminUnit = fiber.NewError(403, "admin tanpa unit tidak dapat memberi role")
+
 // GetAll godoc
 // @Summary Ambil semua user
 // @Description Mengambil seluruh data user
@@ -26,7 +64,7 @@ func NewUserService(repo *repository.UserRepository) *UserService {
 // @Security BearerAuth
 // @Router /api/v1/users [get]
 func (s *UserService) GetAll(c *fiber.Ctx) error {
-	users, err := s.Repo.GetAll()
+	users, err := s.Repo.GetAll(orgScope(c))
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error": "internal server error",
@@ -57,6 +95,10 @@ func (s *UserService) GetAll(c *fiber.Ctx) error {
 // @Router /api/v1/users/{id} [get]
 func (s *UserService) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
+	if !s.inUserScope(c, id) {
+		return c.Status(404).JSON(fiber.Map{"error": "user not found"})
+	}
+
 	user, err := s.Repo.GetByID(id)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{"error": "user not found"})
@@ -92,6 +134,16 @@ func (s *UserService) Create(c *fiber.Ctx) error {
 		RoleID:   req.RoleID,
 	}
 
+	// user baru dari admin unit otomatis masuk unit admin tsb
+	if scope := orgScope(c); scope != nil {
+		if len(scope) == 0 {
+			return c.Status(403).JSON(model.ErrorResponse{
+				Error: errNoAdminUnit.Message,
+			})
+		}
+		user.OrgUnitID = &scope[0]
+	}
+
 	if err := s.Repo.Create(&user); err != nil {
 		return c.Status(500).JSON(model.ErrorResponse{
 			Error: err.Error(),
@@ -119,6 +171,11 @@ func (s *UserService) Create(c *fiber.Ctx) error {
 // @Router /api/v1/users/{id} [put]
 func (s *UserService) Update(c *fiber.Ctx) error {
 	id := c.Params("id")
+	if !s.inUserScope(c, id) {
+		return c.Status(404).JSON(model.ErrorResponse{
+			Error: "user not found",
+		})
+	}
 
 	var req model.UpdateUserRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -135,6 +192,17 @@ func (s *UserService) Update(c *fiber.Ctx) error {
 		IsActive: req.IsActive,
 	}
 
+	unitID, err := s.adminGrantUnit(c, req.RoleID)
+	if e, ok := err.(*fiber.Error); ok {
+		return c.Status(e.Code).JSON(model.ErrorResponse{Error: e.Message})
+	}
+	if err != nil {
+		return c.Status(500).JSON(model.ErrorResponse{
+			Error: "failed to update user",
+		})
+	}
+	user.OrgUnitID = unitID
+
 	if err := s.Repo.Update(id, &user); err != nil {
 		return c.Status(500).JSON(model.ErrorResponse{
 			Error: "failed to update user",
@@ -161,11 +229,16 @@ func (s *UserService) Update(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "User ID"
 // @Success 200 {object} model.MessageResponse
+// @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/users/{id} [delete]
 func (s *UserService) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
+	if !s.inUserScope(c, id) {
+		return c.Status(404).JSON(fiber.Map{"error": "user not found"})
+	}
+
 	if err := s.Repo.Delete(id); err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -189,6 +262,9 @@ func (s *UserService) Delete(c *fiber.Ctx) error {
 // @Router /api/v1/users/{id}/role [put]
 func (s *UserService) UpdateRole(c *fiber.Ctx) error {
 	id := c.Params("id")
+	if !s.inUserScope(c, id) {
+		return c.Status(404).JSON(fiber.Map{"error": "user not found"})
+	}
 
 	var req struct {
 		RoleID string `json:"role_id"`
@@ -206,7 +282,17 @@ func (s *UserService) UpdateRole(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := s.Repo.UpdateRole(id, req.RoleID); err != nil {
+	unitID, err := s.adminGrantUnit(c, req.RoleID)
+	if e, ok := err.(*fiber.Error); ok {
+		return c.Status(e.Code).JSON(fiber.Map{"error": e.Message})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "failed to update role",
+		})
+	}
+
+	if err := s.Repo.UpdateRole(id, req.RoleID, unitID); err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error": "failed to update role",
 		})
@@ -230,3 +316,143 @@ func (s *UserService) UpdateRole(c *fiber.Ctx) error {
 		},
 	})
 }
+
+// ukuran avatar yang disimpan (sisi persegi dalam piksel)
+var avatarSizes = map[string]int{
+	"original": 512,
+	"medium":   256,
+	"thumb":    64,
+}
+
+const maxAvatarSize = 2 * 1024 * 1024
+
+var allowedAvatarTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+func avatarPath(userID, size string) string {
+	return filepath.Join(utils.UploadRoot, "avatars", userID, size+".jpg")
+}
+
+// UploadAvatar godoc
+// @Summary Upload foto profil
+// @Description Upload foto profil user login. Gambar dipotong persegi dan dibuatkan thumbnail (original 512px, medium 256px, thumb 64px)
+// @Tags Users
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Foto profil (jpeg, png, gif, maks 2MB)"
+// @Success 200 {object} model.UploadAvatarResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 413 {object} model.ErrorResponse
+// @Failure 415 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/avatar [post]
+func (s *UserService) UploadAvatar(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "file wajib diupload"})
+	}
+
+	data, contentType, err := utils.ReadUpload(file, maxAvatarSize)
+	if err == utils.ErrFileTooLarge {
+		return c.Status(413).JSON(fiber.Map{"error": "ukuran foto maksimal 2MB"})
+	}
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "file tidak valid"})
+	}
+
+	if !allowedAvatarTypes[contentType] {
+		return c.Status(415).JSON(fiber.Map{"error": "format foto harus jpeg, png, atau gif"})
+	}
+
+	img, err := utils.DecodeImage(data)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "gambar tidak dapat dibaca"})
+	}
+
+	square := utils.CropSquare(img)
+
+	if err := os.MkdirAll(filepath.Dir(avatarPath(userID, "original")), os.ModePerm); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal membuat folder avatar"})
+	}
+
+	urls := map[string]string{}
+	for name, side := range avatarSizes {
+		out, err := utils.EncodeJPEG(utils.Resize(square, side, side))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "gagal memproses gambar"})
+		}
+
+		if err := os.WriteFile(avatarPath(userID, name), out, 0o644); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "gagal menyimpan avatar"})
+		}
+
+		urls[name] = fmt.Sprintf("/api/v1/users/%s/avatar?size=%s", userID, name)
+	}
+
+	return c.JSON(model.UploadAvatarResponse{
+		Status: "success",
+		Data:   urls,
+	})
+}
+
+// GetAvatar godoc
+// @Summary Ambil foto profil
+// @Description Mengambil foto profil user dalam ukuran original, medium, atau thumb
+// @Tags Users
+// @Produce jpeg
+// @Param id path string true "User ID"
+// @Param size query string false "Ukuran (original, medium, thumb)" default(medium)
+// @Success 200 {file} binary
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/{id}/avatar [get]
+func (s *UserService) GetAvatar(c *fiber.Ctx) error {
+	id := c.Params("id")
+	size := c.Query("size", "medium")
+
+	if _, ok := avatarSizes[size]; !ok {
+		return c.Status(400).JSON(fiber.Map{"error": "size harus original, medium, atau thumb"})
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "avatar not found"})
+	}
+
+	path := avatarPath(id, size)
+	if _, err := os.Stat(path); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "avatar not found"})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	c.Type("jpg")
+	return c.SendFile(path)
+}
+
+// DeleteAvatar godoc
+// @Summary Hapus foto profil
+// @Description Menghapus foto profil user login
+// @Tags Users
+// @Produce json
+// @Success 200 {object} model.MessageResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/avatar [delete]
+func (s *UserService) DeleteAvatar(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	if err := os.RemoveAll(filepath.Dir(avatarPath(userID, "original"))); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "gagal menghapus avatar"})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "avatar dihapus",
+	})
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"uas-prestasi/app/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAvatarApp(service *UserService) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "6f1c2a44-2b1e-4c55-9b43-6e0d1f7f9a10")
+		return c.Next()
+	})
+	app.Post("/users/me/avatar", service.UploadAvatar)
+	app.Get("/users/:id/avatar", service.GetAvatar)
+	return app
+}
+
+func multipartFile(t *testing.T, name string, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", name)
+	assert.NoError(t, err)
+	part.Write(content)
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadAvatar_NotImage(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	app := newAvatarApp(NewUserService(nil))
+
+	body, contentType := multipartFile(t, "foto.png", []byte("%PDF-1.4 bukan gambar"))
+	req := httptest.NewRequest("POST", "/users/me/avatar", body)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 415, resp.StatusCode)
+}
+
+func TestUploadAvatar_Success(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	// gambar landscape 300x200 → harus dipotong persegi
+	img := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+
+	app := newAvatarApp(NewUserService(nil))
+
+	body, contentType := multipartFile(t, "foto.png", buf.Bytes())
+	req := httptest.NewRequest("POST", "/users/me/avatar", body)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	thumb, err := os.ReadFile(avatarPath("6f1c2a44-2b1e-4c55-9b43-6e0d1f7f9a10", "thumb"))
+	assert.NoError(t, err)
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(thumb))
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 64, cfg.Width)
+	assert.Equal(t, 64, cfg.Height)
+
+	getReq := httptest.NewRequest("GET", "/users/6f1c2a44-2b1e-4c55-9b43-6e0d1f7f9a10/avatar?size=thumb", nil)
+	getResp, _ := app.Test(getReq)
+	assert.Equal(t, 200, getResp.StatusCode)
+}
+
+func TestUpdateRole_ScopedAdminGrantsAdminInOwnUnit(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM users u WHERE u.id = \$2`).
+		WithArgs(sqlmock.AnyArg(), "u2").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM roles WHERE id::text = \$1 AND name = 'Admin'`).
+		WithArgs("role-admin").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	// user yang belum punya unit ditempatkan di unit admin pemberi role
+	mock.ExpectExec(`UPDATE users SET role_id=\$1, org_unit_id=COALESCE\(\$3, org_unit_id\)`).
+		WithArgs("role-admin", "u2", "dep-te").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM users u\s+LEFT JOIN roles r ON r.id = u.role_id\s+WHERE u.id = \$1`).
+		WithArgs("u2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "full_name", "role_id", "role_name", "is_active", "org_unit_id"}).
+			AddRow("u2", "budi", "budi@kampus.ac.id", "Budi", "role-admin", "Admin", true, "dep-te"))
+
+	service := NewUserService(repository.NewUserRepository(db))
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("org_scope", []string{"dep-te", "prodi-ti"})
+		return c.Next()
+	})
+	app.Put("/users/:id/role", service.UpdateRole)
+
+	req := httptest.NewRequest("PUT", "/users/u2/role", strings.NewReader(`{"role_id":"role-admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,53 @@
+// Command reconcile membandingkan achievement_references di Postgres dengan
+// koleksi achievements di MongoDB dan mencetak laporan JSON.
+//
+//	go run ./cmd/reconcile                       # dry run, hanya laporan
+//	go run ./cmd/reconcile -repair -apply        # perbaiki dokumen Mongo
+//	go run ./cmd/reconcile -quarantine -apply    # karantina data yatim
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"uas-prestasi/app/model"
+	"uas-prestasi/app/repository"
+	"uas-prestasi/app/service"
+	"uas-prestasi/config"
+	"uas-prestasi/database"
+)
+
+func main() {
+	repair := flag.Bool("repair", false, "perbaiki studentId/points/status di Mongo dan antrekan ulang event draft")
+	quarantine := flag.Bool("quarantine", false, "karantina dokumen tanpa reference dan reference tanpa dokumen")
+	apply := flag.Bool("apply", false, "benar-benar menulis perubahan (tanpa flag ini hanya dry run)")
+	flag.Parse()
+
+	config.LoadEnv()
+
+	db := database.ConnectDB()
+	mongoDB := database.ConnectMongo()
+
+	reconciliationService := service.NewReconciliationService(
+		repository.NewReconciliationRepository(db, mongoDB),
+		repository.NewAchievementMongoRepository(mongoDB),
+	)
+
+	dryRun := !*apply
+	report, err := reconciliationService.Run(model.ReconcileRequest{
+		DryRun:     &dryRun,
+		Repair:     *repair,
+		Quarantine: *quarantine,
+	}, "")
+	if err != nil {
+		log.Fatal("rekonsiliasi gagal: ", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"log"
 	"os"
 	"uas-prestasi/config"
 	"uas-prestasi/database"
@@ -37,16 +39,38 @@ func main() {
 
 	achievementRefRepo := repository.NewAchievementReferenceRepository(db)
 	achievementMongoRepo := repository.NewAchievementMongoRepository(mongoDB)
+	if err := achievementMongoRepo.EnsureTextIndex(); err != nil {
+		log.Println("text index achievements gagal dibuat, /achievements/search tidak akan berfungsi:", err)
+	}
+
+	achievementVersionRepo := repository.NewAchievementVersionRepository(mongoDB)
+	if err := achievementVersionRepo.EnsureIndexes(); err != nil {
+		log.Println("index achievement_versions gagal dibuat:", err)
+	}
 
 	reportRepo := repository.NewReportRepository(db, mongoDB)
 
 	studentRepo := repository.NewStudentRepository(db)
 	lecturerRepo := repository.NewLecturerRepository(db)
+	orgUnitRepo := repository.NewOrgUnitRepository(db)
+	achievementTypeRepo := repository.NewAchievementTypeRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	achievementCommentRepo := repository.NewAchievementCommentRepository(db)
+	reconciliationRepo := repository.NewReconciliationRepository(db, mongoDB)
 
 	authService := service.NewAuthService(authRepo)
 	userService := service.NewUserService(userRepo)
 	permService := service.NewPermissionService(permRepo)
-	reportService := service.NewReportService(reportRepo)
+	reportService := service.NewReportService(reportRepo, achievementTypeRepo)
+	orgUnitService := service.NewOrgUnitService(orgUnitRepo)
+	achievementTypeService := service.NewAchievementTypeService(achievementTypeRepo)
+	notificationService := service.NewNotificationService(notificationRepo)
+
+	// menyamakan MongoDB dengan perubahan di Postgres (outbox_events)
+	outboxDispatcher := service.NewOutboxDispatcher(outboxRepo, achievementMongoRepo)
+	outboxDispatcher.Start(context.Background())
+	reconciliationService := service.NewReconciliationService(reconciliationRepo, achievementMongoRepo)
 
 	achievementService := service.NewAchievementService(
 		achievementMongoRepo,
@@ -54,12 +78,16 @@ func main() {
 		db,
 		permService,
 		permRepo,
+		achievementTypeRepo,
+		outboxDispatcher,
+		achievementVersionRepo,
+		achievementCommentRepo,
 	)
 
 	studentService := service.NewStudentService(studentRepo, lecturerRepo)
 	lecturerService := service.NewStudentService(studentRepo, lecturerRepo)
 
-	routes.RegisterRoutes(app, authService, userService, permService, achievementService, reportService, lecturerService, studentService)
+	routes.RegisterRoutes(app, authService, userService, permService, achievementService, reportService, lecturerService, studentService, orgUnitService, achievementTypeService, notificationService, reconciliationService)
 
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
 	app.Listen(":" + os.Getenv("APP_PORT"))
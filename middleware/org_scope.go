@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"uas-prestasi/app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OrgScope mengisi c.Locals("org_scope") dengan id unit organisasi yang boleh
+// diakses user. Tidak diisi kalau user tidak dibatasi unit.
+func OrgScope(orgService *service.OrgUnitService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("user_id").(string)
+
+		scope, err := orgService.ScopeOf(userID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"error": "org scope check failed",
+			})
+		}
+
+		if scope != nil {
+			c.Locals("org_scope", scope)
+		}
+
+		return c.Next()
+	}
+}
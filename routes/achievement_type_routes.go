@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"uas-prestasi/app/service"
+	"uas-prestasi/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AchievementTypeRoutes(app *fiber.App, typeService *service.AchievementTypeService, permService *service.PermissionService) {
+	routes := app.Group("/api/v1/achievement-types",
+		middleware.JWTMiddleware,
+		middleware.RBAC("achievement-type:manage", permService),
+	)
+
+	routes.Get("/", typeService.List)
+	routes.Post("/", typeService.Create)
+	routes.Put("/:code", typeService.Update)
+	routes.Delete("/:code", typeService.Deactivate)
+	routes.Post("/:code/points-preview", typeService.PreviewPoints)
+}
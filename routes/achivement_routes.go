@@ -7,11 +7,22 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func AchievementRoutes(app *fiber.App, achService *service.AchievementService, permService *service.PermissionService) {
+func AchievementRoutes(app *fiber.App, achService *service.AchievementService, typeService *service.AchievementTypeService, permService *service.PermissionService, orgService *service.OrgUnitService) {
 	routes := app.Group("/api/v1/achievements",
 		middleware.JWTMiddleware,
 	)
 
+	// schema details per tipe, didaftarkan sebelum /:id
+	routes.Get("/schemas", typeService.Schemas)
+	routes.Get("/schemas/:type", typeService.Schema)
+
+	// pencarian teks, scope sama dengan list
+	routes.Get("/search",
+		middleware.RBAC("achievement:list", permService),
+		middleware.OrgScope(orgService),
+		achService.Search,
+	)
+
 	routes.Post("/",
 		middleware.RBAC("achievement:create", permService),
 		achService.CreateDraft,
@@ -22,6 +33,12 @@ func AchievementRoutes(app *fiber.App, achService *service.AchievementService, p
 		achService.Submit,
 	)
 
+	// menarik pengajuan memakai izin yang sama dengan submit
+	routes.Post("/:id/withdraw",
+		middleware.RBAC("achievement:submit", permService),
+		achService.Withdraw,
+	)
+
 	routes.Post("/:id/verify",
 		middleware.RBAC("achievement:verify", permService),
 		achService.Verify,
@@ -32,13 +49,20 @@ func AchievementRoutes(app *fiber.App, achService *service.AchievementService, p
 		achService.Reject,
 	)
 
+	routes.Post("/:id/request-revision",
+		middleware.RBAC("achievement:request-revision", permService),
+		achService.RequestRevision,
+	)
+
 	routes.Get("/:id",
 	middleware.RBAC("achievement:detail", permService),
+	middleware.OrgScope(orgService),
 	achService.Detail,
 	)
 
 	routes.Get("/",
 	middleware.RBAC("achievement:list", permService),
+	middleware.OrgScope(orgService),
 	achService.List,
 	)
 
@@ -47,6 +71,11 @@ func AchievementRoutes(app *fiber.App, achService *service.AchievementService, p
 	achService.Update,
 	)
 
+	routes.Patch("/:id",
+	middleware.RBAC("achievement:update", permService),
+	achService.Patch,
+	)
+
 	routes.Delete("/:id",
 	middleware.RBAC("achievement:delete", permService),
 	achService.Delete,
@@ -59,8 +88,40 @@ func AchievementRoutes(app *fiber.App, achService *service.AchievementService, p
 
 	routes.Get("/:id/history",
 	middleware.RBAC("achievement:history", permService),
+	middleware.OrgScope(orgService),
 	achService.History,
 	)
 
+	// snapshot dokumen memuat isi prestasi, jadi izinnya sama dengan detail
+	routes.Get("/:id/versions",
+	middleware.RBAC("achievement:detail", permService),
+	middleware.OrgScope(orgService),
+	achService.Versions,
+	)
+
+	routes.Get("/:id/versions/:version",
+	middleware.RBAC("achievement:detail", permService),
+	middleware.OrgScope(orgService),
+	achService.Version,
+	)
+
+	routes.Get("/:id/diff",
+	middleware.RBAC("achievement:detail", permService),
+	middleware.OrgScope(orgService),
+	achService.Diff,
+	)
+
+	routes.Get("/:id/comments",
+	middleware.RBAC("achievement:comment", permService),
+	middleware.OrgScope(orgService),
+	achService.Comments,
+	)
+
+	routes.Post("/:id/comments",
+	middleware.RBAC("achievement:comment", permService),
+	middleware.OrgScope(orgService),
+	achService.AddComment,
+	)
+
 
 }
@@ -15,23 +15,39 @@ func RegisterRoutes(
 	reportService *service.ReportService,
 	lecturerService *service.StudentService,
 	studentService *service.StudentService,
+	orgService *service.OrgUnitService,
+	achievementTypeService *service.AchievementTypeService,
+	notifService *service.NotificationService,
+	reconcileService *service.ReconciliationService,
 ) {
 
 	// auth
 	AuthRoutes(app, authService)
 
 	// user management
-	UserRoutes(app, userService, permService)
+	UserRoutes(app, userService, permService, orgService)
 
 	// nanti achievement
-	AchievementRoutes(app, achievementService, permService)
+	AchievementRoutes(app, achievementService, achievementTypeService, permService, orgService)
 
 	// nanti students
-	StudentLecturerRoutes(app, studentService, permService)
+	StudentLecturerRoutes(app, studentService, permService, orgService)
 
 	// nanti lecturers
-	StudentLecturerRoutes(app, lecturerService, permService)
+	StudentLecturerRoutes(app, lecturerService, permService, orgService)
 
 	// reports
-	ReportRoutes(app, reportService, permService)
+	ReportRoutes(app, reportService, permService, orgService)
+
+	// fakultas, jurusan, program studi
+	OrgUnitRoutes(app, orgService, permService)
+
+	// katalog jenis prestasi
+	AchievementTypeRoutes(app, achievementTypeService, permService)
+
+	// notifikasi in-app
+	NotificationRoutes(app, notifService)
+
+	// rekonsiliasi Postgres ↔ MongoDB
+	ReconciliationRoutes(app, reconcileService, permService)
 }
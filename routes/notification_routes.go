@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"uas-prestasi/app/service"
+	"uas-prestasi/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// notifikasi selalu milik user yang login, cukup JWT
+func NotificationRoutes(app *fiber.App, notifService *service.NotificationService) {
+	routes := app.Group("/api/v1/notifications",
+		middleware.JWTMiddleware,
+	)
+
+	routes.Get("/", notifService.List)
+	routes.Put("/read-all", notifService.MarkAllRead)
+	routes.Put("/:id/read", notifService.MarkRead)
+}
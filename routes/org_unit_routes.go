@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"uas-prestasi/app/service"
+	"uas-prestasi/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func OrgUnitRoutes(app *fiber.App, orgService *service.OrgUnitService, permService *service.PermissionService) {
+	routes := app.Group("/api/v1",
+		middleware.JWTMiddleware,
+	)
+
+	routes.Get("/org-units",
+		middleware.RBAC("org:read", permService),
+		middleware.OrgScope(orgService),
+		orgService.List,
+	)
+
+	routes.Get("/org-units/:id",
+		middleware.RBAC("org:read", permService),
+		middleware.OrgScope(orgService),
+		orgService.Get,
+	)
+
+	routes.Post("/org-units",
+		middleware.RBAC("org:manage", permService),
+		middleware.OrgScope(orgService),
+		orgService.Create,
+	)
+
+	routes.Put("/org-units/:id",
+		middleware.RBAC("org:manage", permService),
+		middleware.OrgScope(orgService),
+		orgService.Update,
+	)
+
+	routes.Delete("/org-units/:id",
+		middleware.RBAC("org:manage", permService),
+		middleware.OrgScope(orgService),
+		orgService.Delete,
+	)
+
+	// hubungkan mahasiswa, dosen, dan user ke unit
+	routes.Put("/students/:id/study-program",
+		middleware.RBAC("student:manage", permService),
+		middleware.OrgScope(orgService),
+		orgService.AssignStudent,
+	)
+
+	routes.Put("/lecturers/:id/org-unit",
+		middleware.RBAC("org:manage", permService),
+		middleware.OrgScope(orgService),
+		orgService.AssignLecturer,
+	)
+
+	routes.Put("/users/:id/org-unit",
+		middleware.RBAC("user:manage", permService),
+		middleware.OrgScope(orgService),
+		orgService.AssignUser,
+	)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"uas-prestasi/app/service"
+	"uas-prestasi/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ReconciliationRoutes(app *fiber.App, reconcileService *service.ReconciliationService, permService *service.PermissionService) {
+	routes := app.Group("/api/v1/admin/reconciliation",
+		middleware.JWTMiddleware,
+		middleware.RBAC("achievement:reconcile", permService),
+	)
+
+	routes.Post("/", reconcileService.Reconcile)
+}
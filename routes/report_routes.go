@@ -7,19 +7,21 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func ReportRoutes(app *fiber.App, reportService *service.ReportService, permService *service.PermissionService) {
+func ReportRoutes(app *fiber.App, reportService *service.ReportService, permService *service.PermissionService, orgService *service.OrgUnitService) {
 
 	routes := app.Group("/api/v1/reports")
 
 	routes.Get("/statistics",
 		middleware.JWTMiddleware,
 		middleware.RBAC("report:view", permService),
+		middleware.OrgScope(orgService),
 		reportService.Statistics,
 	)
 
 	routes.Get("/student/:id",
 		middleware.JWTMiddleware,
 		middleware.RBAC("report:view", permService),
+		middleware.OrgScope(orgService),
 		reportService.StudentReport,
 	)
 }
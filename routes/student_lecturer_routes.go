@@ -11,6 +11,7 @@ func StudentLecturerRoutes(
 	app *fiber.App,
 	studentService *service.StudentService,
 	permService *service.PermissionService,
+	orgService *service.OrgUnitService,
 ) {
 	routes := app.Group("/api/v1")
 
@@ -18,6 +19,7 @@ func StudentLecturerRoutes(
 	routes.Get("/students",
 		middleware.JWTMiddleware,
 		middleware.RBAC("student:read", permService),
+		middleware.OrgScope(orgService),
 		studentService.GetStudents,
 	)
 
@@ -25,13 +27,23 @@ func StudentLecturerRoutes(
 	routes.Get("/students/:id",
 		middleware.JWTMiddleware,
 		middleware.RBAC("student:read", permService),
+		middleware.OrgScope(orgService),
 		studentService.GetStudent,
 	)
 
+	// Admin → update profil akademik
+	routes.Put("/students/:id",
+		middleware.JWTMiddleware,
+		middleware.RBAC("student:manage", permService),
+		middleware.OrgScope(orgService),
+		studentService.UpdateStudent,
+	)
+
 	// Mahasiswa (own), Dosen (advisee), Admin
 	routes.Get("/students/:id/achievements",
 		middleware.JWTMiddleware,
 		middleware.RBAC("student:list", permService),
+		middleware.OrgScope(orgService),
 		studentService.GetStudentAchievements,
 	)
 
@@ -39,20 +51,86 @@ func StudentLecturerRoutes(
 	routes.Put("/students/:id/advisor",
 		middleware.JWTMiddleware,
 		middleware.RBAC("student:set-advisor", permService),
+		middleware.OrgScope(orgService),
 		studentService.AssignAdvisor,
 	)
 
+	// Admin → assign dosen wali massal (preview / commit)
+	routes.Post("/students/advisors/bulk",
+		middleware.JWTMiddleware,
+		middleware.RBAC("student:set-advisor", permService),
+		middleware.OrgScope(orgService),
+		studentService.BulkAssignAdvisor,
+	)
+
+	// Admin → bagi rata mahasiswa tanpa dosen wali (preview / commit)
+	routes.Post("/students/advisors/auto",
+		middleware.JWTMiddleware,
+		middleware.RBAC("student:set-advisor", permService),
+		middleware.OrgScope(orgService),
+		studentService.AutoAssignAdvisor,
+	)
+
+	// Admin → riwayat dosen wali
+	routes.Get("/students/:id/advisor-history",
+		middleware.JWTMiddleware,
+		middleware.RBAC("student:read", permService),
+		middleware.OrgScope(orgService),
+		studentService.GetAdvisorHistory,
+	)
+
 	// Admin
 	routes.Get("/lecturers",
 		middleware.JWTMiddleware,
 		middleware.RBAC("lecturer:list", permService),
+		middleware.OrgScope(orgService),
 		studentService.GetLecturers,
 	)
 
+	// Admin
+	routes.Post("/lecturers",
+		middleware.JWTMiddleware,
+		middleware.RBAC("lecturer:manage", permService),
+		middleware.OrgScope(orgService),
+		studentService.CreateLecturer,
+	)
+
+	// Dosen → ringkasan antrean verifikasi
+	routes.Get("/lecturers/me/dashboard",
+		middleware.JWTMiddleware,
+		middleware.RBAC("lecturer:dashboard", permService),
+		studentService.GetMyDashboard,
+	)
+
+	// Admin, Dosen
+	routes.Get("/lecturers/:id",
+		middleware.JWTMiddleware,
+		middleware.RBAC("lecturer:list", permService),
+		middleware.OrgScope(orgService),
+		studentService.GetLecturer,
+	)
+
+	// Admin
+	routes.Put("/lecturers/:id",
+		middleware.JWTMiddleware,
+		middleware.RBAC("lecturer:manage", permService),
+		middleware.OrgScope(orgService),
+		studentService.UpdateLecturer,
+	)
+
+	// Admin → nonaktifkan dosen
+	routes.Delete("/lecturers/:id",
+		middleware.JWTMiddleware,
+		middleware.RBAC("lecturer:manage", permService),
+		middleware.OrgScope(orgService),
+		studentService.DeactivateLecturer,
+	)
+
 	// Admin, Dosen
 	routes.Get("/lecturers/:id/advisees",
 		middleware.JWTMiddleware,
 		middleware.RBAC("lecturer:list", permService),
+		middleware.OrgScope(orgService),
 		studentService.GetAdvisees,
 	)
 }
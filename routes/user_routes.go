@@ -7,42 +7,64 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func UserRoutes(app *fiber.App, userService *service.UserService, permService *service.PermissionService) {
+func UserRoutes(app *fiber.App, userService *service.UserService, permService *service.PermissionService, orgService *service.OrgUnitService) {
 	routes := app.Group("/api/v1/users")
 
 	routes.Get("/",
 		middleware.JWTMiddleware,
 		middleware.RBAC("user:manage", permService),
+		middleware.OrgScope(orgService),
 		userService.GetAll,
 	)
 
 	routes.Get("/:id",
 		middleware.JWTMiddleware,
 		middleware.RBAC("user:manage", permService),
+		middleware.OrgScope(orgService),
 		userService.GetByID,
 	)
 
 	routes.Post("/",
 		middleware.JWTMiddleware,
 		middleware.RBAC("user:manage", permService),
+		middleware.OrgScope(orgService),
 		userService.Create,
 	)
 
 	routes.Put("/:id",
 		middleware.JWTMiddleware,
 		middleware.RBAC("user:manage", permService),
+		middleware.OrgScope(orgService),
 		userService.Update,
 	)
 
 	routes.Delete("/:id",
 		middleware.JWTMiddleware,
 		middleware.RBAC("user:manage", permService),
+		middleware.OrgScope(orgService),
 		userService.Delete,
 	)
 
 	routes.Put("/:id/role",
 		middleware.JWTMiddleware,
 		middleware.RBAC("user:manage", permService),
+		middleware.OrgScope(orgService),
 		userService.UpdateRole,
 	)
+
+	// foto profil → cukup login, tidak perlu user:manage
+	routes.Post("/me/avatar",
+		middleware.JWTMiddleware,
+		userService.UploadAvatar,
+	)
+
+	routes.Delete("/me/avatar",
+		middleware.JWTMiddleware,
+		userService.DeleteAvatar,
+	)
+
+	routes.Get("/:id/avatar",
+		middleware.JWTMiddleware,
+		userService.GetAvatar,
+	)
 }
@@ -217,3 +217,440 @@ select * from users;
 select * from students;
 select * from lecturers;
 select * from achievement_references;
+
+-- =============================
+-- profil akademik mahasiswa
+-- =============================
+ALTER TABLE students
+    ADD COLUMN nim VARCHAR(20) UNIQUE,
+    ADD COLUMN study_program VARCHAR(100),
+    ADD COLUMN faculty VARCHAR(100),
+    ADD COLUMN entry_year INT,
+    ADD COLUMN semester INT DEFAULT 1,
+    ADD COLUMN academic_status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (academic_status IN ('active', 'leave', 'graduated', 'dropped_out', 'inactive'));
+
+CREATE INDEX idx_students_study_program ON students (study_program);
+CREATE INDEX idx_students_entry_year ON students (entry_year);
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+('student:manage', 'student', 'update', 'Mengubah profil akademik mahasiswa');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'student:manage'
+WHERE r.name = 'Admin';
+
+-- =============================
+-- unit organisasi: fakultas → jurusan → program studi
+-- =============================
+CREATE TABLE org_units (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    parent_id UUID REFERENCES org_units(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('faculty', 'department', 'study_program')),
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (type, code)
+);
+
+CREATE INDEX idx_org_units_parent ON org_units (parent_id);
+
+ALTER TABLE students ADD COLUMN study_program_id UUID REFERENCES org_units(id);
+ALTER TABLE lecturers ADD COLUMN org_unit_id UUID REFERENCES org_units(id);
+-- scope admin unit (NULL = admin pusat)
+ALTER TABLE users ADD COLUMN org_unit_id UUID REFERENCES org_units(id);
+
+CREATE INDEX idx_students_study_program_id ON students (study_program_id);
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+('org:read', 'org_unit', 'read', 'Melihat fakultas, jurusan, dan program studi'),
+('org:manage', 'org_unit', 'manage', 'Mengelola fakultas, jurusan, dan program studi');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name IN ('org:read', 'org:manage')
+WHERE r.name = 'Admin';
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'org:read'
+WHERE r.name IN ('Dosen Wali', 'Mahasiswa');
+
+-- =============================
+-- data dosen
+-- =============================
+ALTER TABLE lecturers
+    ADD COLUMN nidn VARCHAR(20) UNIQUE,
+    ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT true;
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+('lecturer:manage', 'lecturer', 'manage', 'Menambah, mengubah, dan menonaktifkan dosen');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'lecturer:manage'
+WHERE r.name = 'Admin';
+
+-- =============================
+-- riwayat dosen wali (ended_at NULL = dosen wali saat ini)
+-- =============================
+CREATE TABLE advisor_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    lecturer_id UUID NOT NULL REFERENCES lecturers(id),
+    assigned_by UUID REFERENCES users(id),
+    assigned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMP
+);
+
+CREATE INDEX idx_advisor_history_student ON advisor_history (student_id, assigned_at DESC);
+CREATE UNIQUE INDEX uq_advisor_history_current ON advisor_history (student_id) WHERE ended_at IS NULL;
+
+-- periode awal untuk dosen wali yang sudah ada
+INSERT INTO advisor_history (student_id, lecturer_id)
+SELECT id, advisor_id FROM students WHERE advisor_id IS NOT NULL;
+
+-- =============================
+-- dashboard dosen wali
+-- =============================
+INSERT INTO permissions (name, resource, action, description) VALUES
+('lecturer:dashboard', 'lecturer', 'read', 'Melihat dashboard dosen wali');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'lecturer:dashboard'
+WHERE r.name = 'Dosen Wali';
+
+-- =============================
+-- katalog jenis prestasi
+-- =============================
+CREATE TABLE achievement_types (
+    code VARCHAR(30) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    detail_schema JSONB NOT NULL DEFAULT '{"fields": [], "allow_extra": false}',
+    base_points INTEGER NOT NULL DEFAULT 0 CHECK (base_points >= 0),
+    point_rules JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO achievement_types (code, name, detail_schema, base_points, point_rules) VALUES
+('competition', 'Kompetisi', '{"allow_extra": false, "fields": [
+    {"name": "competitionName", "label": "Nama Kompetisi", "type": "string", "required": true},
+    {"name": "competitionLevel", "label": "Tingkat", "type": "enum", "required": true, "enum": ["international", "national", "regional", "local"]},
+    {"name": "rank", "label": "Peringkat", "type": "number", "required": true, "min": 1},
+    {"name": "medalType", "label": "Medali", "type": "enum", "required": false, "enum": ["gold", "silver", "bronze", "none"]},
+    {"name": "eventDate", "label": "Tanggal Kegiatan", "type": "date", "required": false},
+    {"name": "location", "label": "Lokasi", "type": "string", "required": false},
+    {"name": "organizer", "label": "Penyelenggara", "type": "string", "required": false}
+]}', 10, '[{"field": "competitionLevel", "values": {"international": 100, "national": 75, "regional": 50, "local": 25}}]'),
+('academic', 'Akademik', '{"allow_extra": false, "fields": [
+    {"name": "awardName", "label": "Nama Penghargaan", "type": "string", "required": true},
+    {"name": "awardLevel", "label": "Tingkat", "type": "enum", "required": true, "enum": ["international", "national", "regional", "university", "faculty"]},
+    {"name": "score", "label": "Nilai", "type": "number", "required": false},
+    {"name": "eventDate", "label": "Tanggal Kegiatan", "type": "date", "required": false},
+    {"name": "location", "label": "Lokasi", "type": "string", "required": false},
+    {"name": "organizer", "label": "Penyelenggara", "type": "string", "required": false}
+]}', 10, '[{"field": "awardLevel", "values": {"international": 80, "national": 60, "regional": 40, "university": 25, "faculty": 15}}]'),
+('organization', 'Organisasi', '{"allow_extra": false, "fields": [
+    {"name": "organizationName", "label": "Nama Organisasi", "type": "string", "required": true},
+    {"name": "position", "label": "Jabatan", "type": "string", "required": true},
+    {"name": "periodStart", "label": "Mulai Menjabat", "type": "date", "required": true},
+    {"name": "periodEnd", "label": "Selesai Menjabat", "type": "date", "required": false},
+    {"name": "eventDate", "label": "Tanggal Kegiatan", "type": "date", "required": false},
+    {"name": "location", "label": "Lokasi", "type": "string", "required": false},
+    {"name": "organizer", "label": "Penyelenggara", "type": "string", "required": false}
+]}', 20, '[]'),
+('publication', 'Publikasi', '{"allow_extra": false, "fields": [
+    {"name": "publicationType", "label": "Jenis Publikasi", "type": "enum", "required": true, "enum": ["journal", "conference", "book"]},
+    {"name": "publicationTitle", "label": "Judul Publikasi", "type": "string", "required": true},
+    {"name": "authors", "label": "Penulis", "type": "string_array", "required": true},
+    {"name": "publisher", "label": "Penerbit", "type": "string", "required": true},
+    {"name": "issn", "label": "ISSN/ISBN", "type": "string", "required": false},
+    {"name": "publishedAt", "label": "Tanggal Terbit", "type": "date", "required": false},
+    {"name": "eventDate", "label": "Tanggal Kegiatan", "type": "date", "required": false},
+    {"name": "location", "label": "Lokasi", "type": "string", "required": false},
+    {"name": "organizer", "label": "Penyelenggara", "type": "string", "required": false}
+]}', 20, '[{"field": "publicationType", "values": {"journal": 60, "conference": 40, "book": 50}}]'),
+('certification', 'Sertifikasi', '{"allow_extra": false, "fields": [
+    {"name": "certificationName", "label": "Nama Sertifikasi", "type": "string", "required": true},
+    {"name": "issuedBy", "label": "Diterbitkan Oleh", "type": "string", "required": true},
+    {"name": "certificationNumber", "label": "Nomor Sertifikat", "type": "string", "required": false},
+    {"name": "issuedAt", "label": "Tanggal Terbit", "type": "date", "required": true},
+    {"name": "validUntil", "label": "Berlaku Sampai", "type": "date", "required": false},
+    {"name": "eventDate", "label": "Tanggal Kegiatan", "type": "date", "required": false},
+    {"name": "location", "label": "Lokasi", "type": "string", "required": false},
+    {"name": "organizer", "label": "Penyelenggara", "type": "string", "required": false}
+]}', 30, '[]'),
+('other', 'Lainnya', '{"allow_extra": true, "fields": [
+    {"name": "eventDate", "label": "Tanggal Kegiatan", "type": "date", "required": false},
+    {"name": "location", "label": "Lokasi", "type": "string", "required": false},
+    {"name": "organizer", "label": "Penyelenggara", "type": "string", "required": false}
+]}', 10, '[]');
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+('achievement-type:manage', 'achievement_type', 'manage', 'Mengelola katalog jenis prestasi');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'achievement-type:manage'
+WHERE r.name = 'Admin';
+
+-- =============================
+-- poin otomatis: usulan sistem vs poin yang diberikan dosen
+-- =============================
+ALTER TABLE achievement_references
+    ADD COLUMN suggested_points INTEGER,
+    ADD COLUMN awarded_points INTEGER,
+    ADD COLUMN points_override_reason TEXT;
+
+-- kompetisi: (base + tingkat) × faktor peringkat
+UPDATE achievement_types
+SET point_rules = '[
+    {"field": "competitionLevel", "op": "add", "values": {"international": 100, "national": 75, "regional": 50, "local": 25}},
+    {"field": "rank", "op": "multiply", "values": {"1": 1, "2": 0.8, "3": 0.6}, "default": 0.4}
+]', updated_at = NOW()
+WHERE code = 'competition';
+
+-- publikasi: (base + jenis) × tier indeksasi
+UPDATE achievement_types
+SET detail_schema = jsonb_set(detail_schema, '{fields}', (detail_schema->'fields') || '[
+        {"name": "indexing", "label": "Indeksasi", "type": "enum", "required": false,
+         "enum": ["scopus_q1", "scopus_q2", "scopus_q3", "scopus_q4", "sinta_1", "sinta_2", "sinta_3", "sinta_4", "sinta_5", "sinta_6", "none"]}
+    ]'::jsonb),
+    point_rules = '[
+    {"field": "publicationType", "op": "add", "values": {"journal": 60, "conference": 40, "book": 50}},
+    {"field": "indexing", "op": "multiply", "values": {"scopus_q1": 2, "scopus_q2": 1.75, "scopus_q3": 1.5, "scopus_q4": 1.25, "sinta_1": 1.5, "sinta_2": 1.3, "sinta_3": 1.1}, "default": 1}
+]', updated_at = NOW()
+WHERE code = 'publication';
+
+-- =============================
+-- siklus pengajuan (submit → verified/rejected), termasuk pengajuan ulang
+-- =============================
+CREATE TABLE achievement_submissions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    submission_no INTEGER NOT NULL,
+    submitted_at TIMESTAMP NOT NULL,
+    suggested_points INTEGER,
+    decision VARCHAR(20) CHECK (decision IN ('verified', 'rejected')),
+    decided_at TIMESTAMP,
+    decided_by UUID REFERENCES users(id),
+    rejection_note TEXT,
+    UNIQUE (achievement_id, submission_no)
+);
+
+-- pengajuan yang sudah ada sebelum tabel ini dibuat
+INSERT INTO achievement_submissions (achievement_id, submission_no, submitted_at, suggested_points,
+                                     decision, decided_at, decided_by, rejection_note)
+SELECT id, 1, submitted_at, suggested_points,
+       CASE WHEN status IN ('verified', 'rejected') THEN status END,
+       verified_at, verified_by, rejection_note
+FROM achievement_references
+WHERE submitted_at IS NOT NULL;
+
+-- =============================
+-- status revision_requested (perlu revisi, bukan penolakan)
+-- =============================
+ALTER TABLE achievement_references ADD COLUMN revision_note TEXT;
+
+ALTER TABLE achievement_submissions RENAME COLUMN rejection_note TO note;
+ALTER TABLE achievement_submissions DROP CONSTRAINT achievement_submissions_decision_check;
+ALTER TABLE achievement_submissions ADD CONSTRAINT achievement_submissions_decision_check
+    CHECK (decision IN ('verified', 'rejected', 'revision_requested'));
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+('achievement:request-revision', 'achievement', 'request-revision', 'Meminta mahasiswa merevisi prestasi');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'achievement:request-revision'
+WHERE r.name = 'Dosen Wali';
+
+-- =============================
+-- tarik pengajuan & notifikasi
+-- =============================
+-- diisi saat dosen wali pertama kali membuka pengajuan
+ALTER TABLE achievement_references ADD COLUMN review_started_at TIMESTAMP;
+
+ALTER TABLE achievement_submissions DROP CONSTRAINT achievement_submissions_decision_check;
+ALTER TABLE achievement_submissions ADD CONSTRAINT achievement_submissions_decision_check
+    CHECK (decision IN ('verified', 'rejected', 'revision_requested', 'withdrawn'));
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    message TEXT NOT NULL,
+    achievement_id UUID REFERENCES achievement_references(id) ON DELETE CASCADE,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC);
+
+-- =============================
+-- riwayat status (append-only)
+-- =============================
+-- ditulis di transaksi yang sama dengan setiap perubahan status,
+-- baris tidak pernah di-UPDATE atau di-DELETE
+CREATE TABLE achievement_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor_id UUID REFERENCES users(id),
+    note TEXT,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_status_history_achievement ON achievement_status_history (achievement_id, changed_at);
+
+-- riwayat data lama: draft dibuat, lalu setiap siklus pengajuan
+INSERT INTO achievement_status_history (achievement_id, from_status, to_status, actor_id, changed_at)
+SELECT ar.id, NULL, 'draft', s.user_id, ar.created_at
+FROM achievement_references ar
+JOIN students s ON s.id = ar.student_id;
+
+INSERT INTO achievement_status_history (achievement_id, from_status, to_status, actor_id, changed_at)
+SELECT sub.achievement_id,
+       COALESCE(NULLIF(LAG(sub.decision) OVER (PARTITION BY sub.achievement_id ORDER BY sub.submission_no), 'withdrawn'), 'draft'),
+       'submitted', s.user_id, sub.submitted_at
+FROM achievement_submissions sub
+JOIN achievement_references ar ON ar.id = sub.achievement_id
+JOIN students s ON s.id = ar.student_id;
+
+INSERT INTO achievement_status_history (achievement_id, from_status, to_status, actor_id, note, changed_at)
+SELECT achievement_id, 'submitted',
+       CASE WHEN decision = 'withdrawn' THEN 'draft' ELSE decision END,
+       decided_by, note, decided_at
+FROM achievement_submissions
+WHERE decision IS NOT NULL;
+
+INSERT INTO achievement_status_history (achievement_id, from_status, to_status, actor_id, changed_at)
+SELECT ar.id, 'draft', 'deleted', s.user_id, ar.updated_at
+FROM achievement_references ar
+JOIN students s ON s.id = ar.student_id
+WHERE ar.status = 'deleted';
+
+-- =============================
+-- transactional outbox Postgres → MongoDB
+-- =============================
+-- ditulis di transaksi yang sama dengan perubahan achievement_references,
+-- diterapkan ke MongoDB oleh OutboxDispatcher (idempoten, dengan retry)
+CREATE TABLE outbox_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_outbox_pending ON outbox_events (available_at) WHERE processed_at IS NULL;
+
+-- =============================
+-- rekonsiliasi Postgres ↔ MongoDB
+-- =============================
+INSERT INTO permissions (name, resource, action, description) VALUES
+('achievement:reconcile', 'achievement', 'reconcile', 'Menjalankan rekonsiliasi data prestasi Postgres dan MongoDB');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'achievement:reconcile'
+WHERE r.name = 'Admin';
+
+-- =============================
+-- index untuk pagination cursor (kolom sort + id)
+-- =============================
+CREATE INDEX idx_achievement_refs_created_keyset ON achievement_references (created_at DESC, id DESC);
+CREATE INDEX idx_achievement_refs_submitted_keyset ON achievement_references (submitted_at DESC NULLS LAST, id DESC);
+CREATE INDEX idx_achievement_refs_student_created ON achievement_references (student_id, created_at DESC, id DESC);
+
+-- =============================
+-- snapshot dokumen prestasi per pengajuan
+-- =============================
+-- versi dokumen MongoDB (achievement_versions) yang diajukan; NULL untuk pengajuan lama
+ALTER TABLE achievement_submissions ADD COLUMN document_version INTEGER;
+
+-- =============================
+-- komentar prestasi (mahasiswa ↔ dosen wali, internal untuk admin)
+-- =============================
+CREATE TABLE achievement_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    -- balasan selalu menunjuk komentar teratas thread
+    parent_id UUID REFERENCES achievement_comments(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id),
+    body TEXT NOT NULL,
+    -- fileUrl attachment di dokumen MongoDB
+    attachment_ref TEXT,
+    internal BOOLEAN NOT NULL DEFAULT false,
+    -- clock_timestamp, bukan NOW(): urutan komentar dan posisi baca memakai
+    -- waktu insert sebenarnya, bukan waktu mulai transaksi
+    created_at TIMESTAMP NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX idx_achievement_comments_thread ON achievement_comments (achievement_id, created_at);
+
+-- posisi baca per user untuk hitungan komentar belum dibaca
+CREATE TABLE achievement_comment_reads (
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_read_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (achievement_id, user_id)
+);
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+('achievement:comment', 'achievement', 'comment', 'Membaca dan menulis komentar prestasi'),
+('achievement:comment:internal', 'achievement', 'comment', 'Membaca dan menulis komentar internal prestasi');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'achievement:comment'
+WHERE r.name IN ('Admin', 'Dosen Wali', 'Mahasiswa');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'achievement:comment:internal'
+WHERE r.name = 'Admin';
+
+-- =============================
+-- super admin eksplisit: hanya user dengan flag ini yang tidak dibatasi unit.
+-- admin (role dengan achievement:list:all, user:manage, atau org:manage) dengan
+-- users.org_unit_id kosong tanpa flag tidak punya akses data unit mana pun.
+-- Dosen dan mahasiswa tidak dibatasi unit; aksesnya lewat perwalian/kepemilikan.
+-- =============================
+ALTER TABLE users ADD COLUMN is_super_admin BOOLEAN NOT NULL DEFAULT false;
+
+-- admin pusat yang sudah ada sebelumnya tetap tanpa batas
+UPDATE users SET is_super_admin = true
+WHERE org_unit_id IS NULL
+  AND role_id = (SELECT id FROM roles WHERE name = 'Admin');
+
+-- =============================
+-- permintaan revisi tidak lagi mengisi verified_at / verified_by,
+-- keputusannya cukup tercatat di achievement_submissions
+-- =============================
+UPDATE achievement_references
+SET verified_at = NULL, verified_by = NULL
+WHERE status = 'revision_requested';
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+)
+
+// batas dimensi supaya file kecil tapi resolusi raksasa tidak menghabiskan memory
+const MaxImageDimension = 6000
+
+var ErrImageTooLarge = errors.New("dimensi gambar terlalu besar")
+
+// DecodeImage decode jpeg/png/gif setelah mengecek dimensinya lebih dulu
+func DecodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Width > MaxImageDimension || cfg.Height > MaxImageDimension {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// CropSquare memotong bagian tengah gambar menjadi persegi.
+// Area transparan diberi latar putih karena hasil akhirnya JPEG.
+func CropSquare(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+
+	offset := image.Point{
+		X: b.Min.X + (b.Dx()-side)/2,
+		Y: b.Min.Y + (b.Dy()-side)/2,
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, offset, draw.Over)
+
+	return dst
+}
+
+// Resize mengubah ukuran gambar dengan box filter (rata-rata piksel sumber
+// yang tercakup tiap piksel tujuan), cukup bagus untuk thumbnail
+func Resize(src *image.RGBA, width, height int) *image.RGBA {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		sy0 := y * sh / height
+		sy1 := max((y+1)*sh/height, sy0+1)
+
+		for x := 0; x < width; x++ {
+			sx0 := x * sw / width
+			sx1 := max((x+1)*sw/width, sx0+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				off := src.PixOffset(sb.Min.X+sx0, sb.Min.Y+sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint64(src.Pix[off])
+					g += uint64(src.Pix[off+1])
+					b += uint64(src.Pix[off+2])
+					a += uint64(src.Pix[off+3])
+					n++
+					off += 4
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const UploadRoot = "uploads"
+
+var (
+	ErrEmptyFile    = errors.New("file kosong")
+	ErrFileTooLarge = errors.New("ukuran file melebihi batas")
+)
+
+// SaveUpload menyimpan file multipart ke uploads/<dir> dengan nama unik,
+// lalu mengembalikan path relatif yang disimpan
+func SaveUpload(c *fiber.Ctx, file *multipart.FileHeader, dir string) (string, error) {
+	uploadDir := filepath.Join(UploadRoot, dir)
+	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	fileName := fmt.Sprintf("%s_%s", uuid.NewString(), filepath.Base(file.Filename))
+	uploadPath := fmt.Sprintf("%s/%s", uploadDir, fileName)
+
+	if err := c.SaveFile(file, uploadPath); err != nil {
+		return "", err
+	}
+
+	return uploadPath, nil
+}
+
+// ReadUpload membaca isi file multipart maksimal maxSize byte dan
+// mengembalikan content type hasil sniffing isi file (bukan header dari client)
+func ReadUpload(file *multipart.FileHeader, maxSize int64) ([]byte, string, error) {
+	if file.Size == 0 {
+		return nil, "", ErrEmptyFile
+	}
+	if file.Size > maxSize {
+		return nil, "", ErrFileTooLarge
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	// baca satu byte lebih supaya header Size yang dipalsukan tetap ketahuan
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) == 0 {
+		return nil, "", ErrEmptyFile
+	}
+	if int64(len(data)) > maxSize {
+		return nil, "", ErrFileTooLarge
+	}
+
+	return data, http.DetectContentType(data), nil
+}